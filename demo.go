@@ -41,6 +41,7 @@ var currentTheme *wid.Theme // the theme selected
 var darkMode = false
 var oldWindowSize image.Point // the current window size, used to detect changes
 var win *app.Window           // The main window
+var form *wid.Form            // The form with the widgets of the main window
 var thb *wid.Theme            // Secondary theme used for the color-shifting button
 var progress float32
 var sliderValue1 float32
@@ -51,19 +52,36 @@ var icon *widget.Icon
 var addIcon *wid.Icon
 var homeIcon *wid.Icon
 var checkIcon *wid.Icon
-var upIcon *wid.Icon
-var downIcon *wid.Icon
 var count float64
 var startTime time.Time
 
+// Variables bound to the edits in column1
+var userName = "Ole Karlsen"
+var userAddress string
+var userAge = 21
+var userHeight = 1.80
+var userEmail string
+
+// registration is edited on the form page, with a form made from the struct fields.
+type registration struct {
+	ID      int     `wid:"label=Number,readonly"`
+	Person  *person `wid:"label=Participant"`
+	Paid    bool
+	Amount  float64 `wid:"format=%0.2f,width=200"`
+	Comment string
+}
+
+var reg = registration{ID: 1001, Person: &data[0], Amount: 250}
+
+// validationResult is the outcome of the last click on the validate button
+var validationResult string
+
 func main() {
 	flag.StringVar(&mode, "mode", "default", "Select window as fullscreen, maximized, centered or default")
 	flag.StringVar(&fontSize, "fontsize", "large", "Select font size medium,small,large")
 	flag.Parse()
 	addIcon, _ = wid.NewIcon(icons.ContentAdd)
 	checkIcon, _ = wid.NewIcon(icons.ActionCheckCircle)
-	upIcon, _ = wid.NewIcon(icons.HardwareKeyboardArrowUp)
-	downIcon, _ = wid.NewIcon(icons.HardwareKeyboardArrowDown)
 	homeIcon, _ = wid.NewIcon(icons.ActionHome)
 	makePersons(100)
 	ic, err := widget.NewIcon(icons.ContentAdd)
@@ -113,6 +131,15 @@ func onClick() {
 	}
 }
 
+func onValidate() {
+	errs := form.Validate()
+	if len(errs) == 0 {
+		validationResult = "All fields are valid"
+		return
+	}
+	validationResult = fmt.Sprintf("%d errors, first is: %v", len(errs), errs[0])
+}
+
 func update() {
 	onSwitchMode(darkMode)
 }
@@ -151,28 +178,38 @@ func onCenter() {
 	win.Center()
 }
 
+// The edits in the two columns have keys, so that the text typed by the user is kept when the
+// form is rebuilt after a change of window size, font size or dark mode.
 func column1(th *wid.Theme) layout.Widget {
-	return wid.MakeList(th, wid.Occupy,
+	return wid.KeyedList(th, "column1", wid.Occupy,
 		wid.Label(th, "Scrollable list of fields with labels", wid.Middle()),
-		wid.Edit(th, wid.Lbl("Value 1")),
-		wid.Edit(th, wid.Lbl("Value 2")),
-		wid.Edit(th, wid.Lbl("Value 3")),
-		wid.Edit(th, wid.Lbl("Value 4")),
-		wid.Edit(th, wid.Lbl("Value 5")),
-		wid.Edit(th, wid.Lbl("Value 6")),
-		wid.Edit(th, wid.Lbl("Value 7")))
+		wid.Edit(th, wid.Lbl("Name"), wid.Key("column1.value1"), wid.BindString(&userName), wid.Required()),
+		wid.Edit(th, wid.Lbl("Address"), wid.Key("column1.value2"), wid.BindString(&userAddress)),
+		wid.Edit(th, wid.Lbl("Age"), wid.Key("column1.value3"), wid.BindInt(&userAge), wid.Range(0, 150)),
+		wid.Edit(th, wid.Lbl("Height"), wid.Key("column1.value4"), wid.BindFloat(&userHeight, "%0.2f")),
+		wid.Value(th, func() string {
+			return fmt.Sprintf("%s, %s, %d years, %0.2fm", userName, userAddress, userAge, userHeight)
+		}),
+		wid.Edit(th, wid.Lbl("E-mail"), wid.Key("column1.value5"), wid.BindString(&userEmail),
+			wid.Regexp(`^[^@ ]+@[^@ ]+\.[a-z]+$`)),
+		wid.Row(th, nil, nil,
+			wid.Button(th, "Validate", wid.Handler(onValidate)),
+			wid.Value(th, func() string { return validationResult }),
+		),
+		wid.Edit(th, wid.Lbl("Value 6"), wid.Key("column1.value6")),
+		wid.Edit(th, wid.Lbl("Value 7"), wid.Key("column1.value7")))
 }
 
 func column2(th *wid.Theme) layout.Widget {
-	return wid.MakeList(th, wid.Occupy,
+	return wid.KeyedList(th, "column2", wid.Occupy,
 		wid.Label(th, "Scrollable list of fields without labels", wid.Middle()),
-		wid.Edit(th, wid.Hint("Value 1")),
-		wid.Edit(th, wid.Hint("Value 2")),
-		wid.Edit(th, wid.Hint("Value 3")),
-		wid.Edit(th, wid.Hint("Value 4")),
-		wid.Edit(th, wid.Hint("Value 5")),
-		wid.Edit(th, wid.Hint("Value 6")),
-		wid.Edit(th, wid.Hint("Value 7")))
+		wid.Edit(th, wid.Hint("Value 1"), wid.Key("column2.value1")),
+		wid.Edit(th, wid.Hint("Value 2"), wid.Key("column2.value2")),
+		wid.Edit(th, wid.Hint("Value 3"), wid.Key("column2.value3")),
+		wid.Edit(th, wid.Hint("Value 4"), wid.Key("column2.value4")),
+		wid.Edit(th, wid.Hint("Value 5"), wid.Key("column2.value5")),
+		wid.Edit(th, wid.Hint("Value 6"), wid.Key("column2.value6")),
+		wid.Edit(th, wid.Hint("Value 7"), wid.Key("column2.value7")))
 }
 
 func demo(th *wid.Theme) layout.Widget {
@@ -190,8 +227,8 @@ func demo(th *wid.Theme) layout.Widget {
 		),
 		wid.Separator(th, unit.Dp(2), wid.Color(th.SashColor)),
 		wid.SplitVertical(th, 0.25,
-			wid.SplitHorizontal(th, 0.5, column1(th), column2(th)),
-			wid.MakeList(th, wid.Occupy,
+			wid.SplitHorizontal(th, 0.5, column1(th), column2(th), wid.Key("columns")),
+			wid.KeyedList(th, "buttons", wid.Occupy,
 				wid.Col(
 					wid.Row(th, nil, nil,
 						wid.RadioButton(th, &mode, "windowed", "windowed"),
@@ -262,6 +299,7 @@ func demo(th *wid.Theme) layout.Widget {
 				),
 				wid.Separator(th, unit.Dp(2.0)),
 				wid.ImageFromJpgFile("gopher.jpg")),
+			wid.Key("rows"),
 		),
 	)
 }
@@ -276,6 +314,13 @@ var (
 	dropDownValue7 = 1
 	dropDownValue8 = 1
 	dropDownValue9 = 1
+	// comboText is the text of the combobox that takes values that are not in the list.
+	comboText  = "Option 7"
+	comboValue = 6
+	// multiValue is the items selected in the multi-select dropdown.
+	multiValue = []int{0, 2, 4, 7}
+	// fruitValue is the key of the item selected in the dropdown with grouped items.
+	fruitValue = "cherry"
 )
 
 func dropDownDemo(th *wid.Theme) layout.Widget {
@@ -294,8 +339,24 @@ func dropDownDemo(th *wid.Theme) layout.Widget {
 			// DropDown defaults to max size, here filling a complete row across the form.
 			wid.DropDown(th, &dropDownValue5, []string{"Option X", "Option Y", "Option Z"}).Layout,
 			wid.Separator(th, unit.Dp(2.0), wid.Pads(20, 0)),
-			wid.Label(th, "A very long list with scrolling, with fixed width 250"),
-			wid.DropDown(th, &dropDownValue6, longList, wid.W(250)).Layout,
+			wid.Label(th, "A very long list with scrolling and search, with fixed width 250"),
+			wid.DropDown(th, &dropDownValue6, longList, wid.W(250), wid.Key("longList"), wid.Searchable()).Layout,
+			wid.Label(th, "A combobox that also takes text that is not in the list"),
+			wid.DropDown(th, &comboValue, longList, wid.W(250), wid.Key("combo"), wid.FreeText(&comboText), wid.MaxRows(8)).Layout,
+			wid.Label(th, "A dropdown where several options can be selected"),
+			wid.MultiDropDown(th, &multiValue, longList[:20], wid.W(350), wid.Key("multi")).Layout,
+			wid.Label(th, "A dropdown with groups, icons and a disabled item, bound to the item keys"),
+			wid.DropDownItems(th, &fruitValue, []wid.Item{
+				{Text: "Fruit", Header: true},
+				{Key: "apple", Text: "Apple", Icon: checkIcon, Secondary: "12"},
+				{Key: "banana", Text: "Banana", Secondary: "3"},
+				{Key: "cherry", Text: "Cherry", Icon: homeIcon, Secondary: "40"},
+				{Separator: true},
+				{Text: "Berries", Header: true},
+				{Key: "blueberry", Text: "Blueberry"},
+				{Key: "cloudberry", Text: "Cloudberry", Disabled: true},
+				{Key: "strawberry", Text: "Strawberry"},
+			}, wid.W(250)).Layout,
 			wid.DropDown(th, &dropDownValue7, []string{"Option 1 with very long text", "Option 2", "Option 3"}, wid.W(250)).Layout,
 			dropdown1.Layout,
 			dropdown2.Layout,
@@ -309,14 +370,19 @@ var page = "Layout"
 var topRowPadding = layout.Inset{Top: unit.Dp(8), Bottom: unit.Dp(8), Left: unit.Dp(8), Right: unit.Dp(8)}
 
 // Column widths are given in units of approximately one average character width (en).
-var largeColWidth = []float32{2, 40, 40, 40, 40}
-var smallColWidth = []float32{2, 20, 0.9, 6, 15}
-var fracColWidth = []float32{2, 20.3, 0.3, 6, 0.14}
+var largeColWidth = []float32{40, 40, 40, 40}
+var smallColWidth = []float32{20, 0.9, 6, 15}
+var fracColWidth = []float32{20.3, 0.3, 6, 0.14}
 var dropdown1 *wid.DropDownStyle
 var dropdown2 *wid.DropDownStyle
 
 func setup() {
 	th := currentTheme
+	if form == nil {
+		form = wid.NewForm(th)
+	}
+	form.SetTheme(th)
+	form.Init()
 	dropdown1 = wid.DropDown(th, &dropDownValue1, []string{"Option A", "Option B", "Option C", "Option D"})
 	dropdown2 = wid.DropDown(th, &dropDownValue2, []string{"Option D", "Option E", "Option F"})
 	var currentPage layout.Widget
@@ -326,24 +392,37 @@ func setup() {
 		currentPage = Grid(th, wid.Overlay, data, smallColWidth)
 	} else if page == "Grid3" {
 		currentPage = Grid(th, wid.Overlay, data[:5], fracColWidth)
+	} else if page == "Paged" {
+		currentPage = PagedGrid(th)
+	} else if page == "Tree" {
+		currentPage = Tree(th)
 	} else if page == "Layout" {
 		currentPage = dropDownDemo(th)
 	} else if page == "Buttons" {
 		currentPage = demo(th)
 	} else if page == "KitchenV" {
 		currentPage = kitchenV(th)
+	} else if page == "Form" {
+		currentPage = wid.MakeList(th, wid.Occupy,
+			wid.StructForm(th, &reg, wid.Key("reg")),
+			wid.Value(th, func() string {
+				return fmt.Sprintf("%s, %d years, paid=%v, amount %0.2f", reg.Person.Name, reg.Person.Age, reg.Paid, reg.Amount)
+			}),
+		)
 	}
-	wid.Init()
 	if page == "KitchenX" || page == "KitchenV" {
-		wid.Setup(currentPage)
+		form.Setup(currentPage)
 	} else {
-		wid.Setup(wid.Col(
+		form.Setup(wid.Col(
 			wid.Pad(topRowPadding, wid.Row(th, nil, nil,
 				wid.RadioButton(th, &page, "Grid1", "Grid1", wid.Do(update)),
 				wid.RadioButton(th, &page, "Grid2", "Grid2", wid.Do(update)),
 				wid.RadioButton(th, &page, "Grid3", "Grid3", wid.Do(update)),
+				wid.RadioButton(th, &page, "Paged", "Paged", wid.Do(update)),
+				wid.RadioButton(th, &page, "Tree", "Tree", wid.Do(update)),
 				wid.RadioButton(th, &page, "Buttons", "Buttons", wid.Do(update)),
 				wid.RadioButton(th, &page, "Layout", "DropDowns", wid.Do(update)),
+				wid.RadioButton(th, &page, "Form", "Form", wid.Do(update)),
 				wid.RadioButton(th, &page, "KitchenX", "KitchenX", wid.Do(update)),
 				wid.RadioButton(th, &page, "KitchenV", "KitchenV", wid.Do(update)),
 				wid.Checkbox(th, "Dark mode", &darkMode, onSwitchMode),
@@ -355,7 +434,7 @@ func setup() {
 }
 
 func handleFrameEvents(e system.FrameEvent) {
-	if oldWindowSize.X != e.Size.X || oldWindowSize.Y != e.Size.Y || fontSize != oldFontSize || wid.Root == nil {
+	if oldWindowSize.X != e.Size.X || oldWindowSize.Y != e.Size.Y || fontSize != oldFontSize || form.Root() == nil {
 		switch fontSize {
 		case "medium", "Medium":
 			currentTheme.TextSize = unit.Dp(float32(e.Size.Y) / 80)
@@ -377,7 +456,7 @@ func handleFrameEvents(e system.FrameEvent) {
 	if page == "KitchenX" {
 		kitchenX(gtx, th)
 	} else {
-		wid.Root(gtx)
+		form.Layout(gtx)
 	}
 	// Apply the actual screen drawing
 	e.Frame(gtx.Ops)
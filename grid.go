@@ -6,20 +6,22 @@ package main
 // It scrolls verticaly and horizontaly and implements highlighting of rows.
 
 import (
+	"encoding/json"
 	"fmt"
 	"gio-v/wid"
-	"sort"
+	"os"
+	"path/filepath"
+	"time"
 
 	"gioui.org/layout"
-	"gioui.org/unit"
 )
 
 type person struct {
-	Selected bool
-	Name     string
+	Selected bool   `wid:"-"`
+	Name     string `wid:"required"`
 	Age      int
 	Address  string
-	Status   int
+	Status   int `wid:"label=Gender,options=Male|Female|Other"`
 }
 
 var data = []person{
@@ -47,100 +49,171 @@ func makePersons(n int) {
 	data = data[0:n]
 }
 
-var dir bool
-var sortCol int
+var genders = []string{"Male", "Female", "Other"}
 
-func onNameClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Name >= data[j].Name })
+// exportResult is the outcome of the last export of the grid.
+var exportResult string
+
+// groupByGender shows the persons in groups.
+var groupByGender bool
+
+// columnsFile returns the file that keeps the order, visibility and widths of the grid columns between runs,
+// in the configuration directory of the user.
+func columnsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
 	}
-	dir = !dir
-	sortCol = 1
-	update()
+	return filepath.Join(dir, "gio-v", "grid-columns.json"), nil
 }
 
-func onAddressClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Address < data[j].Address })
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Address >= data[j].Address })
+// columnsRestored is set when the column layout has been read from columnsFile.
+var columnsRestored bool
+
+// Grid is a widget that lays out the grid. This is all that is needed.
+func Grid(th *wid.Theme, anchor wid.AnchorStrategy, data []person, colWidth []float32) layout.Widget {
+	columns := []wid.Column{
+		{Title: "Name", Field: "Name", Width: colWidth[0], Editable: true, Filter: true},
+		{Title: "Address", Field: "Address", Width: colWidth[1], Editable: true, Filter: true},
+		{Title: "Age", Field: "Age", Width: colWidth[2], Editable: true, Filter: true, Aggregate: wid.Average},
+		{Title: "Gender", Field: "Status", Width: colWidth[3], Options: genders, Filter: true,
+			Cell: func(th *wid.Theme, p interface{}) layout.Widget {
+				return wid.DropDown(th, &p.(*person).Status, genders).Layout
+			}},
+	}
+	options := []wid.Option{wid.SelectedField("Selected"), wid.Key("grid"), wid.FrozenColumns(1),
+		wid.ExportFile("persons.csv", func(err error) {
+			if err != nil {
+				exportResult = err.Error()
+			} else {
+				exportResult = "Exported to persons.csv"
+			}
+		})}
+	if groupByGender {
+		options = append(options, wid.GroupBy("Status"))
 	}
-	dir = !dir
-	sortCol = 2
-	update()
+	table := wid.Table(th, anchor, data, columns, options...)
+	if !columnsRestored {
+		columnsRestored = true
+		var l wid.ColumnLayout
+		if name, err := columnsFile(); err == nil {
+			if b, err := os.ReadFile(name); err == nil && json.Unmarshal(b, &l) == nil {
+				table.SetColumnLayout(l)
+			}
+		}
+	}
+	saveColumns := func() {
+		name, err := columnsFile()
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(name), 0755)
+		}
+		if err == nil {
+			b, _ := json.MarshalIndent(table.ColumnLayout(), "", "  ")
+			err = os.WriteFile(name, b, 0644)
+		}
+		if err != nil {
+			exportResult = err.Error()
+		} else {
+			exportResult = "Saved the columns to " + name
+		}
+	}
+	return wid.Col(
+		wid.Row(th, nil, []float32{0.3, 0.12, 0.12, 0.12, 0.34},
+			table.SearchEdit(wid.Key("grid.search")),
+			table.ExportButton(wid.Hint("Write the selected rows, or all rows, to persons.csv")),
+			wid.Button(th, "Save columns", wid.Handler(saveColumns),
+				wid.Hint("Drag the headers to move columns, and right click them to hide columns")),
+			wid.Checkbox(th, "Group", &groupByGender, func(bool) { update() }),
+			wid.Value(th, func() string {
+				visible, total := table.Count()
+				return fmt.Sprintf("Showing %d of %d persons, %d selected. %s",
+					visible, total, table.Selection().Len(), exportResult)
+			}),
+		),
+		table.Layout,
+	)
 }
 
-func onAgeClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Age < data[j].Age })
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Age >= data[j].Age })
-	}
-	dir = !dir
-	sortCol = 3
-	update()
+// entry is a file or a folder in the tree demo.
+type entry struct {
+	Name string
+	Kind string
+	Size int
 }
 
-// selectAll is not used, but is controlled from the heading checkbox.
-// It could be used to check/uncheck all boxes in the table
-var selectAll bool
-var nameIcon *wid.Icon
-var addressIcon *wid.Icon
-var ageIcon *wid.Icon
-
-func getIcon(colNo int) *wid.Icon {
-	if sortCol == colNo {
-		if dir {
-			return upIcon
+// folder returns the entries in the folder p. They are made up when the folder is first expanded,
+// like a debugger would fetch the fields of a variable.
+func folder(p interface{}) interface{} {
+	e := p.(*entry)
+	var entries []entry
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("%s/%c", e.Name, 'a'+i-1)
+		if len(e.Name) < 12 && i <= 2 {
+			entries = append(entries, entry{Name: name, Kind: "Folder"})
+		} else {
+			entries = append(entries, entry{Name: name + ".go", Kind: "File", Size: 1000 * i * len(e.Name)})
 		}
-		return downIcon
 	}
-	return nil
+	return entries
 }
 
-// Grid is a widget that lays out the grid. This is all that is needed.
-func Grid(th *wid.Theme, anchor wid.AnchorStrategy, data []person, colWidth []float32) layout.Widget {
-	nameIcon = getIcon(1)
-	addressIcon = getIcon(2)
-	ageIcon = getIcon(3)
-	// Setup theme for heading.
-	thh := *th
-	thh.OnBackground = wid.WithAlpha(th.Primary, 210)
-	thh.Background = th.Surface
-	thh.LabelPadding = layout.UniformInset(th.TextSize.Scale(0.4))
-	// Setup theme for grid labels.
-	thg := *th
-	thg.Background = th.Surface
-	thg.LabelPadding = layout.UniformInset(th.TextSize.Scale(0.35))
-	// Configure a row with headings.
-	heading := wid.Row(&thh, &selectAll, colWidth,
-		wid.Checkbox(&thh, "", &selectAll, onCheck),
-		wid.HeaderButton(&thh, "Name", wid.Handler(onNameClick), wid.W(9999), wid.BtnIcon(nameIcon)),
-		wid.HeaderButton(&thh, "Address", wid.Handler(onAddressClick), wid.W(9999), wid.BtnIcon(addressIcon)),
-		wid.HeaderButton(&thh, "Age", wid.Handler(onAgeClick), wid.W(9999), wid.BtnIcon(ageIcon)),
-		wid.Label(&thh, "Gender", wid.Bold()),
-	)
-	var lines []layout.Widget
-	lines = append(lines, heading)
-	lines = append(lines, wid.Separator(th, unit.Dp(2.0), wid.W(9999)))
-	for i := 0; i < len(data); i++ {
-		w := wid.Row(&thg, &data[i].Selected, colWidth,
-			wid.Checkbox(&thg, "", &data[i].Selected, nil),
-			wid.Label(&thg, data[i].Name),
-			wid.Label(&thg, data[i].Address),
-			wid.Label(&thg, fmt.Sprintf("%d", data[i].Age)),
-			wid.DropDown(&thg, &data[i].Status, []string{"Male", "Female", "Other"}).Layout,
-		)
-		lines = append(lines, w, wid.Separator(th, unit.Dp(0.5), wid.W(9999)))
+var entries = []entry{{Name: "cmd", Kind: "Folder"}, {Name: "internal", Kind: "Folder"}, {Name: "go.mod", Kind: "File", Size: 420}}
+
+// Tree is a table where the folders can be expanded.
+func Tree(th *wid.Theme) layout.Widget {
+	columns := []wid.Column{
+		{Title: "Name", Field: "Name", Width: 0.6, Filter: true},
+		{Title: "Kind", Field: "Kind", Width: 0.2},
+		{Title: "Size", Field: "Size", Width: 0.2},
 	}
-	return wid.MakeList(&thg, anchor, lines...)
+	return wid.Table(th, wid.Occupy, entries, columns, wid.Key("tree"),
+		wid.TreeChildren(folder, func(p interface{}) bool { return p.(*entry).Kind == "Folder" })).Layout
 }
 
-func onCheck(b bool) {
-	// Called when the header checkbox is clicked. It will set or clear all rows.
-	for i := 0; i < len(data); i++ {
-		data[i].Selected = b
+// source gives the persons to the paged grid slowly, like a database on a network would.
+var source *wid.SliceSource
+
+// showPages shows one page of the paged grid at a time, instead of scrolling through all persons.
+var (
+	showPages bool
+	gridPage  int
+	gridSize  = 100
+)
+
+// PagedGrid is a table that gets its rows a page at a time, showing placeholders until they arrive.
+func PagedGrid(th *wid.Theme) layout.Widget {
+	if source == nil {
+		persons := make([]person, 10000)
+		for i := range persons {
+			persons[i] = data[i%len(data)]
+			persons[i].Age = 20 + i%60
+		}
+		source = wid.NewSliceSource(persons)
+		source.Delay = 500 * time.Millisecond
 	}
+	columns := []wid.Column{
+		{Title: "Name", Field: "Name", Width: 0.3},
+		{Title: "Address", Field: "Address", Width: 0.4},
+		{Title: "Age", Field: "Age", Width: 0.1},
+		{Title: "Gender", Field: "Status", Width: 0.2, Options: genders},
+	}
+	options := []wid.Option{wid.Source(source, win.Invalidate), wid.Key("paged")}
+	if showPages {
+		options = append(options, wid.Pages(&gridPage, &gridSize))
+	}
+	table := wid.Table(th, wid.Occupy, []person(nil), columns, options...)
+	return wid.Col(
+		wid.Row(th, nil, []float32{0.2, 0.8},
+			wid.Checkbox(th, "Pages", &showPages, func(bool) { update() }),
+			wid.Value(th, func() string {
+				_, total := table.Count()
+				if err := table.Err(); err != nil {
+					return err.Error()
+				}
+				return fmt.Sprintf("%d persons", total)
+			}),
+		),
+		table.Paginator(wid.Key("paged.pages")),
+		table.Layout,
+	)
 }
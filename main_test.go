@@ -37,7 +37,7 @@ func DoLayout(b *testing.B, show string, n int) {
 		if page == "KitchenX" {
 			kitchenX(gtx, th)
 		} else {
-			wid.Root(gtx)
+			form.Layout(gtx)
 		}
 	}
 }
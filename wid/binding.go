@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// binding connects the text of an edit to a variable.
+type binding interface {
+	// format returns the value of the variable as text.
+	format() string
+	// parse sets the variable from the text s, or returns an error if s is not a valid value.
+	parse(s string) error
+}
+
+type stringBinding struct {
+	v *string
+}
+
+type intBinding struct {
+	v *int
+}
+
+type floatBinding struct {
+	v   *float64
+	fmt string
+}
+
+// reflectBinding binds to a string or number of any kind, like a struct field.
+type reflectBinding struct {
+	v   reflect.Value
+	fmt string
+}
+
+// BindString is an option parameter that binds the text of an edit to the string s.
+func BindString(s *string) EditOption {
+	return func(e *EditDef) {
+		e.value = stringBinding{v: s}
+	}
+}
+
+// BindInt is an option parameter that binds the text of an edit to the integer n.
+// The variable is only changed when the text is a valid integer.
+func BindInt(n *int) EditOption {
+	return func(e *EditDef) {
+		e.value = intBinding{v: n}
+	}
+}
+
+// BindFloat is an option parameter that binds the text of an edit to the float f.
+// The value is shown using the fmt.Sprintf format string, like "%0.2f".
+// The variable is only changed when the text is a valid number.
+func BindFloat(f *float64, format string) EditOption {
+	return func(e *EditDef) {
+		e.value = floatBinding{v: f, fmt: format}
+	}
+}
+
+func (b stringBinding) format() string {
+	return *b.v
+}
+
+func (b stringBinding) parse(s string) error {
+	*b.v = s
+	return nil
+}
+
+func (b intBinding) format() string {
+	return strconv.Itoa(*b.v)
+}
+
+func (b intBinding) parse(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", s)
+	}
+	*b.v = n
+	return nil
+}
+
+func (b floatBinding) format() string {
+	if b.fmt == "" {
+		return strconv.FormatFloat(*b.v, 'g', -1, 64)
+	}
+	return fmt.Sprintf(b.fmt, *b.v)
+}
+
+func (b floatBinding) parse(s string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", s)
+	}
+	*b.v = f
+	return nil
+}
+
+// supported returns true if the kind of the value can be edited as text.
+func (b reflectBinding) supported() bool {
+	switch b.v.Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func (b reflectBinding) format() string {
+	switch b.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(b.v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(b.v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if b.fmt == "" {
+			return strconv.FormatFloat(b.v.Float(), 'g', -1, b.v.Type().Bits())
+		}
+		return fmt.Sprintf(b.fmt, b.v.Float())
+	}
+	return b.v.String()
+}
+
+func (b reflectBinding) parse(s string) error {
+	switch b.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, b.v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+		b.v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, b.v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a positive integer", s)
+		}
+		b.v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), b.v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		b.v.SetFloat(f)
+	default:
+		b.v.SetString(s)
+	}
+	return nil
+}
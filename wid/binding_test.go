@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntBinding(t *testing.T) {
+	n := 5
+	b := intBinding{v: &n}
+	if s := b.format(); s != "5" {
+		t.Errorf("format() = %q, want %q", s, "5")
+	}
+	if err := b.parse(" 42 "); err != nil || n != 42 {
+		t.Errorf("parse(\" 42 \") = %v, n = %d, want nil and 42", err, n)
+	}
+	if err := b.parse("4x"); err == nil || n != 42 {
+		t.Errorf("parse(\"4x\") = %v, n = %d, want an error and 42", err, n)
+	}
+}
+
+func TestFloatBinding(t *testing.T) {
+	f := 1.5
+	b := floatBinding{v: &f, fmt: "%0.2f"}
+	if s := b.format(); s != "1.50" {
+		t.Errorf("format() = %q, want %q", s, "1.50")
+	}
+	if s := (floatBinding{v: &f}).format(); s != "1.5" {
+		t.Errorf("format() without a format string = %q, want %q", s, "1.5")
+	}
+	if err := b.parse("2.25"); err != nil || f != 2.25 {
+		t.Errorf("parse(\"2.25\") = %v, f = %v, want nil and 2.25", err, f)
+	}
+	if err := b.parse("abc"); err == nil || f != 2.25 {
+		t.Errorf("parse(\"abc\") = %v, f = %v, want an error and 2.25", err, f)
+	}
+}
+
+func TestReflectBinding(t *testing.T) {
+	var v struct {
+		I8  int8
+		U   uint16
+		F32 float32
+		S   string
+	}
+	field := func(name string) reflectBinding {
+		return reflectBinding{v: reflect.ValueOf(&v).Elem().FieldByName(name)}
+	}
+	tests := []struct {
+		field string
+		text  string
+		ok    bool
+	}{
+		{"I8", "-12", true},
+		{"I8", "300", false},
+		{"U", "65535", true},
+		{"U", "-1", false},
+		{"F32", "0.25", true},
+		{"F32", "x", false},
+		{"S", " text ", true},
+	}
+	for _, test := range tests {
+		b := field(test.field)
+		err := b.parse(test.text)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: parse(%q) = %v, want ok %v", test.field, test.text, err, test.ok)
+			continue
+		}
+		if test.ok && b.format() != test.text {
+			t.Errorf("%s: format() = %q after parse(%q)", test.field, b.format(), test.text)
+		}
+	}
+	if v.I8 != -12 || v.U != 65535 || v.F32 != 0.25 || v.S != " text " {
+		t.Errorf("fields are %+v", v)
+	}
+	if (reflectBinding{v: reflect.ValueOf(true)}).supported() {
+		t.Errorf("bool is supported")
+	}
+}
@@ -81,8 +81,13 @@ func HeaderButton(th *Theme, label string, options ...Option) func(gtx C) D {
 }
 
 func aButton(style ButtonStyle, th *Theme, label string, options ...Option) func(gtx C) D {
+	return newButton(style, th, label, options...).Layout
+}
+
+// newButton returns a button, for widgets that need to change the button after it is made.
+func newButton(style ButtonStyle, th *Theme, label string, options ...Option) *ButtonDef {
 	b := ButtonDef{}
-	b.SetupTabs()
+	b.SetupTabs(th.form)
 	// Setup default values
 	b.th = th
 	b.Text = label
@@ -95,28 +100,34 @@ func aButton(style ButtonStyle, th *Theme, label string, options ...Option) func
 	for _, option := range options {
 		option.apply(&b)
 	}
+	if old, ok := th.form.keep(b.key, &b).(*ButtonDef); ok {
+		b.Clickable.keep(&old.Clickable)
+	}
 	b.Tooltip = PlatformTooltip(th, b.hint)
-	return func(gtx C) D {
-		if style == Contained || style == Round {
-			b.fg = th.OnPrimary
-			b.bg = th.Primary
+	return &b
+}
+
+// Layout draws the button and handles clicks.
+func (b *ButtonDef) Layout(gtx C) D {
+	if b.Style == Contained || b.Style == Round {
+		b.fg = b.th.OnPrimary
+		b.bg = b.th.Primary
+	} else {
+		b.fg = b.th.OnBackground
+		b.bg = color.NRGBA{}
+	}
+	if b.Widget.fgColor.A != 0 {
+		b.bg = b.Widget.fgColor
+		if Luminance(b.Widget.fgColor) > 127 {
+			b.fg = RGB(0x000000)
 		} else {
-			b.fg = th.OnBackground
-			b.bg = color.NRGBA{}
-		}
-		if b.Widget.fgColor.A != 0 {
-			b.bg = b.Widget.fgColor
-			if Luminance(b.Widget.fgColor) > 127 {
-				b.fg = RGB(0x000000)
-			} else {
-				b.fg = RGB(0xFFFFFF)
-			}
+			b.fg = RGB(0xFFFFFF)
 		}
-		dims := b.layout(gtx)
-		b.HandleClick()
-		pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
-		return dims
 	}
+	dims := b.layout(gtx)
+	b.HandleClick()
+	pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
+	return dims
 }
 
 func (b BtnOption) apply(cfg interface{}) {
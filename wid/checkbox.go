@@ -30,10 +30,28 @@ type CheckBoxDef struct {
 	UncheckedStateIcon *Icon
 	Value              *bool
 	changed            bool
+	// State is the state of a tri-state checkbox, used instead of Value, and stateHandler is called when
+	// the user changes it.
+	State                  *CheckState
+	IndeterminateStateIcon *Icon
+	stateHandler           func(s CheckState)
 }
 
+// CheckState is the state of a tri-state checkbox.
+type CheckState int
+
+const (
+	// Unchecked is the state of a checkbox that is not checked.
+	Unchecked CheckState = iota
+	// Checked is the state of a checked checkbox.
+	Checked
+	// Indeterminate is the state of a checkbox that is partly checked, like a checkbox for a group of
+	// items where only some of them are checked. It can only be set by the program.
+	Indeterminate
+)
+
 // Checkbox returns a widget that can be checked, with label, initial state and handler function
-func Checkbox(th *Theme, label string, State *bool, handler func(b bool)) func(gtx C) D {
+func Checkbox(th *Theme, label string, State *bool, handler func(b bool), options ...Option) func(gtx C) D {
 	c := &CheckBoxDef{
 		Label:              label,
 		Value:              State,
@@ -46,7 +64,7 @@ func Checkbox(th *Theme, label string, State *bool, handler func(b bool)) func(g
 		UncheckedStateIcon: th.CheckBoxUnchecked,
 	}
 	c.handler = handler
-	c.SetupTabs()
+	c.setup(th, options...)
 	return func(gtx C) D {
 		dims := c.layout(gtx)
 		c.HandleToggle(c.Value, &c.changed)
@@ -55,9 +73,57 @@ func Checkbox(th *Theme, label string, State *bool, handler func(b bool)) func(g
 	}
 }
 
+// TriCheckbox returns a checkbox that can also be indeterminate, with label, state and handler function.
+// The indeterminate state is set by the program, usually from the state of the items the checkbox is for.
+// A click checks an unchecked or indeterminate checkbox, and unchecks a checked one.
+func TriCheckbox(th *Theme, label string, state *CheckState, handler func(s CheckState), options ...Option) func(gtx C) D {
+	c := &CheckBoxDef{
+		Label:                  label,
+		State:                  state,
+		TextColor:              th.OnBackground,
+		IconColor:              th.OnBackground,
+		TextSize:               th.TextSize.Scale(1.0),
+		Size:                   th.TextSize.Scale(1.5),
+		shaper:                 th.Shaper,
+		CheckedStateIcon:       th.CheckBoxChecked,
+		UncheckedStateIcon:     th.CheckBoxUnchecked,
+		IndeterminateStateIcon: th.CheckBoxIndeterminate,
+	}
+	c.stateHandler = handler
+	c.setup(th, options...)
+	return func(gtx C) D {
+		dims := c.layout(gtx)
+		for c.Clicked() {
+			if *c.State == Checked {
+				*c.State = Unchecked
+			} else {
+				*c.State = Checked
+			}
+			if c.stateHandler != nil {
+				c.stateHandler(*c.State)
+			}
+		}
+		pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
+		return dims
+	}
+}
+
+// setup links the checkbox into the form, and takes over the state of the checkbox it replaces.
+func (c *CheckBoxDef) setup(th *Theme, options ...Option) {
+	c.SetupTabs(th.form)
+	c.th = th
+	c.Apply(options...)
+	if old, ok := th.form.keep(c.key, c).(*CheckBoxDef); ok {
+		c.Clickable.keep(&old.Clickable)
+	}
+}
+
 func (c *CheckBoxDef) layout(gtx C) D {
 	icon := c.UncheckedStateIcon
-	if *c.Value {
+	switch {
+	case c.State != nil && *c.State == Indeterminate && c.IndeterminateStateIcon != nil:
+		icon = c.IndeterminateStateIcon
+	case c.State != nil && *c.State == Checked, c.State == nil && *c.Value:
 		icon = c.CheckedStateIcon
 	}
 	dims := layout.Flex{Alignment: layout.Middle}.Layout(gtx,
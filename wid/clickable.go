@@ -8,8 +8,6 @@ import (
 
 	"gioui.org/op/clip"
 
-	"gioui.org/layout"
-
 	"gioui.org/f32"
 	"gioui.org/gesture"
 	"gioui.org/io/key"
@@ -66,47 +64,24 @@ type Press struct {
 	Cancelled bool
 }
 
-// Prev is a global value used to save the previous widget that was tab-able
-var Prev Focuser
-
-// First will store the initial focused widget
-var First Focuser
-
-// Root is the root widget (usually a list), and is the root of the widget tree
-var Root layout.Widget
-
-// Init will initialize the widget tree
-func Init() {
-	// Drop all old data
-	Root = nil
-	First = nil
-	Prev = nil
-}
-
-// Setup will initialze root
-func Setup(w layout.Widget) {
-	Root = w
-	// Focus the component to be focused at startup.
-	if First != nil {
-		First.Focus()
-	}
-}
-
 // Disabled returns true if the widget is disabled
 func (c *Clickable) Disabled() bool {
 	return c.disabled
 }
 
-// SetupTabs is used to set next/previous widget for tabbing between widgets
-func (c *Clickable) SetupTabs() {
-	if First == nil {
-		First = c
+// SetupTabs is used to link the widget into the tab sequence of the form f.
+// Widgets made without a form are not reached by tabbing.
+func (c *Clickable) SetupTabs(f *Form) {
+	if f != nil {
+		f.add(c)
 	}
-	if Prev != nil {
-		c.SetPrev(Prev)
-		Prev.SetNext(c)
+}
+
+// keep takes over the focus from the widget that c replaces.
+func (c *Clickable) keep(old *Clickable) {
+	if old.focused || old.requestFocus {
+		c.requestFocus = true
 	}
-	Prev = c
 }
 
 // HandleClick will call the callback function
@@ -347,12 +322,23 @@ func (c *Clickable) HandleClicks(gtx C) D {
 
 // LayoutBorder will draw a border around the widget
 func LayoutBorder(e *Clickable, th *Theme) func(gtx C) D {
+	return layoutBorder(e, th, false)
+}
+
+// layoutBorder will draw a border around the widget, using the error color when invalid is set.
+func layoutBorder(e *Clickable, th *Theme, invalid bool) func(gtx C) D {
 	return func(gtx C) D {
 		outline := f32.Rectangle{Max: f32.Point{
 			X: float32(gtx.Constraints.Min.X),
 			Y: float32(gtx.Constraints.Min.Y),
 		}}
-		if e.Focused() {
+		if invalid {
+			width := th.BorderThickness
+			if e.Focused() {
+				width = th.BorderThicknessActive
+			}
+			paintBorder(gtx, outline, th.Error, width, th.CornerRadius)
+		} else if e.Focused() {
 			paintBorder(gtx, outline, MulAlpha(th.Primary, 255), th.BorderThicknessActive, th.CornerRadius)
 		} else if e.Hovered() {
 			paintBorder(gtx, outline, MulAlpha(th.Primary, 140), th.BorderThickness, th.CornerRadius)
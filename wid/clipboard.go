@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// blockRect returns the first and last row and column of the block of cells from the block anchor to the cursor.
+func (t *TableDef) blockRect() (r0, r1, c0, c1 int) {
+	r0, r1 = t.blockRow, t.curRow
+	if r0 > r1 {
+		r0, r1 = r1, r0
+	}
+	c0, c1 = t.blockCol, t.curCol
+	if c0 > c1 {
+		c0, c1 = c1, c0
+	}
+	return r0, r1, c0, c1
+}
+
+// inBlock returns true if the cell in row r and column col is in the marked block.
+func (t *TableDef) inBlock(r, col int) bool {
+	if !t.block {
+		return false
+	}
+	r0, r1, c0, c1 := t.blockRect()
+	return r >= r0 && r <= r1 && col >= c0 && col <= c1
+}
+
+// copyText returns the cells that are copied with Ctrl-C as tab separated text. It is the marked block
+// if there is one, else the selected rows, else the cell at the cursor.
+func (t *TableDef) copyText() string {
+	var rows [][]string
+	switch {
+	case t.block:
+		r0, r1, c0, c1 := t.blockRect()
+		for r := r0; r <= r1 && r < len(t.view); r++ {
+			e := t.elem(t.view[r])
+			var row []string
+			for col := c0; col <= c1 && col < len(t.columns); col++ {
+				row = append(row, t.text(e, col))
+			}
+			rows = append(rows, row)
+		}
+	case t.anySelected():
+		// A table with a data source only copies the rows fetched so far, so that the frame is not held up.
+		ids, elem := t.loadedRows()
+		rows = t.records(t.exportColumns(), true, ids, elem)
+	case t.curRow < len(t.view) && t.curCol < len(t.columns):
+		rows = [][]string{{t.text(t.elem(t.view[t.curRow]), t.curCol)}}
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = '\t'
+	_ = w.WriteAll(rows)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// PasteDone is an option parameter that sets the function called after text is pasted into the table
+// with Ctrl-V. The error tells how many cells were skipped because the text was not valid for the field,
+// and is nil if all cells that can be edited were set.
+func PasteDone(done func(err error)) TableOption {
+	return func(t *TableDef) {
+		t.pasteDone = done
+	}
+}
+
+// paste writes tab separated text to the cells from the cursor, or from the top left cell of the marked block,
+// like a spreadsheet does. Cells that can not be edited are skipped, and so are cells where the text is not
+// valid for the field, which are reported to the function set by PasteDone. Changed is called for each row
+// where a field was changed.
+func (t *TableDef) paste(s string) {
+	r := csv.NewReader(strings.NewReader(s))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		// Not valid quoting, so take the text as it is.
+		records = nil
+		for _, line := range strings.Split(strings.TrimRight(s, "\r\n"), "\n") {
+			records = append(records, strings.Split(strings.TrimSuffix(line, "\r"), "\t"))
+		}
+	}
+	row, col := t.curRow, t.curCol
+	if t.block {
+		row, _, col, _ = t.blockRect()
+	}
+	invalid := 0
+	var first error
+	for i, rec := range records {
+		if row+i >= len(t.view) {
+			break
+		}
+		id := t.view[row+i]
+		e := t.elem(id)
+		changed := false
+		for j, s := range rec {
+			if col+j >= len(t.columns) {
+				break
+			}
+			b := t.cellBinding(e, col+j)
+			if b == nil {
+				continue
+			}
+			old := b.format()
+			if err := b.parse(s); err != nil {
+				if invalid == 0 {
+					first = err
+				}
+				invalid++
+				continue
+			}
+			changed = changed || b.format() != old
+		}
+		if changed {
+			t.Changed(id)
+		}
+	}
+	if t.pasteDone != nil {
+		var err error
+		if invalid > 0 {
+			err = fmt.Errorf("wid: %d cells were not pasted, the first because %v", invalid, first)
+		}
+		t.pasteDone(err)
+	}
+}
+
+// cellBinding returns the binding used to edit and paste the field of column col in the struct e,
+// or nil if the cell can not be edited. A bool is set from true or false, and an integer with options
+// from the name of an option.
+func (t *TableDef) cellBinding(e reflect.Value, col int) binding {
+	c := t.columns[col]
+	if !c.Editable || c.Cell != nil || t.fields[col] == nil {
+		return nil
+	}
+	v := e.FieldByIndex(t.fields[col])
+	switch {
+	case v.Kind() == reflect.Bool:
+		return boolBinding{v: v}
+	case len(c.Options) > 0 && isInteger(v.Kind()):
+		return optionBinding{v: v, options: c.Options}
+	}
+	if b := (reflectBinding{v: v}); b.supported() {
+		return b
+	}
+	return nil
+}
+
+// boolBinding binds to a bool field.
+type boolBinding struct {
+	v reflect.Value
+}
+
+func (b boolBinding) format() string {
+	return strconv.FormatBool(b.v.Bool())
+}
+
+func (b boolBinding) parse(s string) error {
+	x, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%q is not true or false", s)
+	}
+	b.v.SetBool(x)
+	return nil
+}
+
+// optionBinding binds to an integer field, of any kind, that is the index of one of the options.
+type optionBinding struct {
+	v       reflect.Value
+	options []string
+}
+
+func (b optionBinding) format() string {
+	if n := intValue(b.v); n >= 0 && n < int64(len(b.options)) {
+		return b.options[n]
+	}
+	return reflectBinding{v: b.v}.format()
+}
+
+func (b optionBinding) parse(s string) error {
+	for i, o := range b.options {
+		if !strings.EqualFold(o, strings.TrimSpace(s)) {
+			continue
+		}
+		switch b.v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			b.v.SetUint(uint64(i))
+		default:
+			b.v.SetInt(int64(i))
+		}
+		return nil
+	}
+	return fmt.Errorf("%q is not one of the options", s)
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"strings"
+	"testing"
+
+	"gioui.org/layout"
+)
+
+type stock struct {
+	Name  string
+	Kind  string
+	Count int
+	Size  uint8
+	Sold  bool
+}
+
+func TestPaste(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	data := []stock{{"Apple", "Tree", 3, 0, false}, {"Banana", "Herb", 2, 0, false}, {"Cherry", "Tree", 5, 0, false}}
+	columns := []Column{
+		{Title: "Name", Field: "Name", Cell: func(th *Theme, p interface{}) layout.Widget { return empty }, Editable: true},
+		{Title: "Count", Field: "Count", Editable: true, Aggregate: Sum},
+		{Title: "Size", Field: "Size", Editable: true, Options: []string{"Small", "Large", "Large"}},
+		{Title: "Sold", Field: "Sold", Editable: true},
+	}
+	var pasteErr error
+	table := Table(th, Occupy, &data, columns, GroupBy("Kind"), PasteDone(func(err error) { pasteErr = err }))
+	for g := range table.groups {
+		table.groupStats(g)
+	}
+	// The view has Banana first, in the Herb group, and then Apple and Cherry.
+	table.paste("x\t2\tlarge\tfalse\ny\t7\tmedium\ttrue")
+	want := []stock{{"Apple", "Tree", 7, 0, true}, {"Banana", "Herb", 2, 1, false}, {"Cherry", "Tree", 5, 0, false}}
+	for i := range data {
+		if data[i] != want[i] {
+			t.Errorf("row %d is %v after paste, want %v", i, data[i], want[i])
+		}
+	}
+	if pasteErr == nil || !strings.Contains(pasteErr.Error(), "1 cells") {
+		t.Errorf("paste reported %v, want one invalid cell", pasteErr)
+	}
+	if table.groups[1].stats != nil {
+		t.Errorf("the stats of the changed group were kept")
+	}
+	for g := range table.groups {
+		table.groupStats(g)
+	}
+	table.paste("z\t2\tLarge\tfalse")
+	if pasteErr != nil {
+		t.Errorf("paste reported %v for valid cells", pasteErr)
+	}
+	if table.groups[0].stats == nil || table.groups[1].stats == nil {
+		t.Errorf("the stats were dropped for rows that did not change")
+	}
+}
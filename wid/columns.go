@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"image"
+	"math"
+	"reflect"
+
+	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+// ColumnLayout is the order, visibility and widths of the columns of a table, as set by the user.
+// It can be saved, for example as JSON, and given back to the table with SetColumnLayout.
+type ColumnLayout struct {
+	// Columns is the columns in the order they are shown, including the hidden ones.
+	Columns []ColumnState
+}
+
+// ColumnState is the state of one column in a ColumnLayout.
+type ColumnState struct {
+	// Name identifies the column. It is the field of the column, or the title if the column has no field.
+	Name string
+	// Hidden is set when the column is not shown.
+	Hidden bool
+	// Width is the width set by dragging the header border, in the same units as a fixed Column.Width,
+	// or zero if the width has not been set.
+	Width float32
+}
+
+// columnName returns the name of the column in a ColumnLayout.
+func columnName(c Column) string {
+	if c.Field != "" {
+		return c.Field
+	}
+	return c.Title
+}
+
+// ColumnLayout returns the order, visibility and widths of the columns.
+func (t *TableDef) ColumnLayout() ColumnLayout {
+	t.saveColumns()
+	var l ColumnLayout
+	for _, i := range t.order {
+		s := ColumnState{Name: columnName(t.all[i]), Hidden: !t.visible[i]}
+		if t.sized != nil && t.allWidths[i] > 0 && t.unit > 0 {
+			s.Width = float32(math.Round(float64(t.allWidths[i])/float64(t.unit)*10) / 10)
+		}
+		l.Columns = append(l.Columns, s)
+	}
+	return l
+}
+
+// SetColumnLayout sets the order, visibility and widths of the columns, usually to a layout saved from
+// ColumnLayout. Columns in l that the table does not have are skipped, and columns of the table that
+// are not in l are shown after the others. It is used at the next layout of the table.
+func (t *TableDef) SetColumnLayout(l ColumnLayout) {
+	t.later(func() {
+		t.saveColumns()
+		index := make(map[string]int)
+		for i, c := range t.all {
+			index[columnName(c)] = i
+		}
+		used := make([]bool, len(t.all))
+		var order []int
+		sized := false
+		for _, s := range l.Columns {
+			i, ok := index[s.Name]
+			if !ok || used[i] {
+				continue
+			}
+			used[i] = true
+			order = append(order, i)
+			t.visible[i] = !s.Hidden
+			t.allWidths[i] = 0
+			if s.Width > 0 {
+				t.allWidths[i] = int(s.Width*t.unit + 0.5)
+				sized = true
+			}
+		}
+		for i := range t.all {
+			if !used[i] {
+				order = append(order, i)
+				t.visible[i] = true
+				t.allWidths[i] = 0
+			}
+		}
+		t.order = order
+		t.sized = nil
+		if sized {
+			t.sized = []int{}
+		}
+		t.arrangeColumns()
+	})
+}
+
+// showColumns makes the columns shown, and their fields, from the order and visibility set by the user.
+// At least one column is always shown.
+func (t *TableDef) showColumns() {
+	typ := t.data.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	t.columns, t.fields, t.shown = nil, nil, nil
+	for _, i := range t.order {
+		if t.visible[i] {
+			t.columns = append(t.columns, t.all[i])
+			t.fields = append(t.fields, fieldIndex(typ, t.all[i].Field))
+			t.shown = append(t.shown, i)
+		}
+	}
+	if len(t.shown) == 0 && len(t.order) > 0 {
+		t.visible[t.order[0]] = true
+		t.showColumns()
+	}
+}
+
+// saveColumns keeps the widths and filters of the columns shown, so that they follow the columns
+// when they are moved, and are used again when a hidden column is shown.
+func (t *TableDef) saveColumns() {
+	for col, i := range t.shown {
+		if col < len(t.filters) {
+			t.allFilters[i] = t.filters[col]
+		}
+		if c := t.firstCol() + col; c < len(t.sized) {
+			t.allWidths[i] = t.sized[c]
+		}
+	}
+}
+
+// arrangeColumns shows the columns in the order and with the visibility set by the user, after saveColumns.
+// The sort column and the cursor follow their columns, and the header is made again.
+func (t *TableDef) arrangeColumns() {
+	sortCol, curCol := -1, -1
+	if t.sortCol >= 0 && t.sortCol < len(t.shown) {
+		sortCol = t.shown[t.sortCol]
+	}
+	if t.curCol < len(t.shown) {
+		curCol = t.shown[t.curCol]
+	}
+	t.showColumns()
+	t.filters = make([]colFilter, len(t.shown))
+	t.sortCol = -1
+	for col, i := range t.shown {
+		t.filters[col] = t.allFilters[i]
+		if i == sortCol {
+			t.sortCol = col
+		}
+		if i == curCol {
+			t.curCol = col
+		}
+	}
+	if t.sized != nil {
+		// Columns that have not been sized are left at 0, and get their width in layoutWidths,
+		// when the width of the table is known.
+		sized := make([]int, t.firstCol(), t.firstCol()+len(t.shown))
+		copy(sized, t.sized)
+		for _, i := range t.shown {
+			sized = append(sized, t.allWidths[i])
+		}
+		t.sized = sized
+	}
+	t.curCol = clampInt(t.curCol, 0, len(t.columns)-1)
+	t.editor = nil
+	t.block = false
+	t.totalRow = nil
+	t.totals = nil
+	// The filters of hidden columns no longer apply.
+	t.dirty = true
+	t.th.form.detach(func() { t.heading = t.makeHeading(t.th) })
+	t.sort()
+}
+
+// moveColumn moves the column shown at from to the position of the column shown at to.
+func (t *TableDef) moveColumn(from, to int) {
+	a, b := t.shown[from], t.shown[to]
+	for j, i := range t.order {
+		if i == a {
+			t.order[j] = b
+		} else if i == b {
+			t.order[j] = a
+		}
+	}
+	t.saveColumns()
+	t.arrangeColumns()
+}
+
+// moving handles the dragging of header cells, which moves the column past the columns it is dragged over.
+// It returns true if a column was moved.
+func (t *TableDef) moving(gtx C) bool {
+	if len(t.movers) != len(t.columns) {
+		t.movers = make([]gesture.Drag, len(t.columns))
+		t.moveCol = -1
+	}
+	moved := false
+	for i := range t.movers {
+		for _, e := range t.movers[i].Events(gtx.Metric, gtx, gesture.Horizontal) {
+			switch e.Type {
+			case pointer.Press:
+				t.moveCol = i
+			case pointer.Release, pointer.Cancel:
+				t.moveCol = -1
+			case pointer.Drag:
+				if moved || t.moveCol < 0 || t.moveCol >= len(t.columns) {
+					break
+				}
+				// The position is in the coordinates of the header, which is where the movers are added.
+				x := int(e.Position.X)
+				if next := t.moveCol + 1; next < len(t.columns) && x > t.cellLeft(next)+t.widths[t.firstCol()+next]/2 {
+					t.moveColumn(t.moveCol, next)
+					t.moveCol, moved = next, true
+				} else if prev := t.moveCol - 1; prev >= 0 && x < t.cellLeft(prev)+t.widths[t.firstCol()+prev]/2 {
+					t.moveColumn(t.moveCol, prev)
+					t.moveCol, moved = prev, true
+				}
+			}
+		}
+	}
+	return moved
+}
+
+// cellLeft returns the left side of the header cell of column col.
+func (t *TableDef) cellLeft(col int) int {
+	i := t.firstCol() + col
+	x := 0
+	for _, w := range t.widths[:i] {
+		x += w
+	}
+	if i < t.frozenCells() {
+		x += t.list.Hpos
+	}
+	return x
+}
+
+// layoutMovers adds the areas that are dragged to move the columns, over the header cells.
+// The header buttons get the clicks too, so that a click without dragging sorts the column.
+func (t *TableDef) layoutMovers(gtx C, height int) {
+	if len(t.movers) != len(t.columns) || len(t.widths) != t.firstCol()+len(t.columns) {
+		return
+	}
+	defer pointer.PassOp{}.Push(gtx.Ops).Pop()
+	for col := range t.columns {
+		x := t.cellLeft(col)
+		st := clip.Rect(image.Rect(x, 0, x+t.widths[t.firstCol()+col], height)).Push(gtx.Ops)
+		t.movers[col].Add(gtx.Ops)
+		st.Pop()
+	}
+}
+
+// makeMenu returns the column menu, with a checkbox for each column that shows or hides it.
+// The checkboxes are not in the tab sequence of the form.
+func (t *TableDef) makeMenu() layout.Widget {
+	var items []layout.Widget
+	t.th.form.detach(func() {
+		for _, i := range t.order {
+			items = append(items, Checkbox(t.th, t.all[i].Title, &t.visible[i], func(b bool) {
+				t.later(func() {
+					t.saveColumns()
+					t.arrangeColumns()
+				})
+			}))
+		}
+	})
+	return Col(items...)
+}
+
+// layoutMenu opens the column menu when the header is clicked with the secondary button, and draws it
+// above the other widgets while it is open. A click outside the menu closes it.
+func (t *TableDef) layoutMenu(gtx C, size image.Point) {
+	for _, e := range gtx.Events(&t.menuOpen) {
+		if e, ok := e.(pointer.Event); ok && e.Type == pointer.Press && e.Buttons.Contain(pointer.ButtonSecondary) {
+			t.menuOpen = true
+			t.menuPos = image.Pt(int(e.Position.X), int(e.Position.Y))
+		}
+	}
+	for _, e := range gtx.Events(&t.menuPos) {
+		if e, ok := e.(pointer.Event); ok && e.Type == pointer.Press {
+			t.menuOpen = false
+		}
+	}
+	st := pointer.PassOp{}.Push(gtx.Ops)
+	area := clip.Rect{Max: size}.Push(gtx.Ops)
+	pointer.InputOp{Tag: &t.menuOpen, Types: pointer.Press}.Add(gtx.Ops)
+	area.Pop()
+	st.Pop()
+	if !t.menuOpen {
+		return
+	}
+	macro := op.Record(gtx.Ops)
+	// The area outside the menu catches the clicks that close it.
+	area = clip.Rect(image.Rect(-inf, -inf, inf, inf)).Push(gtx.Ops)
+	pointer.InputOp{Tag: &t.menuPos, Types: pointer.Press}.Add(gtx.Ops)
+	area.Pop()
+	op.Offset(layout.FPt(t.menuPos)).Add(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	menu := op.Record(gtx.Ops)
+	dims := t.th.LabelPadding.Layout(gtx, t.menu)
+	call := menu.Stop()
+	outline := f32.Rectangle{Max: layout.FPt(dims.Size)}
+	area = clip.Rect{Max: dims.Size}.Push(gtx.Ops)
+	paint.Fill(gtx.Ops, t.th.Background)
+	paintBorder(gtx, outline, t.th.OnBackground, t.th.BorderThickness, unit.Value{})
+	// Clicks between the checkboxes must not close the menu.
+	pointer.InputOp{Tag: &t.menu, Types: pointer.Press}.Add(gtx.Ops)
+	call.Add(gtx.Ops)
+	area.Pop()
+	op.Defer(gtx.Ops, macro.Stop())
+}
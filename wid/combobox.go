@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"strings"
+	"unicode"
+
+	"gioui.org/gesture"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+)
+
+// makeSearch makes the edit and the list of matching items of a searchable dropdown.
+func (b *DropDownStyle) makeSearch(th *Theme) {
+	var options []Option
+	if b.key != "" {
+		options = append(options, Key(b.key+".search"))
+	}
+	b.search = newEdit(th, options...)
+	b.search.onKey = b.searchKey
+	// A kept edit has the text the user was typing.
+	if b.search.Len() == 0 {
+		b.setText(b.value())
+	}
+	b.matchList = newLazyList(th, Overlay, func() int { return len(b.matches) }, func(i int) layout.Widget {
+		return b.option(th, b.matches[i])
+	})
+	b.find("")
+}
+
+// value returns the text of the item selected, or the free text.
+func (b *DropDownStyle) value() string {
+	if b.freeText != nil {
+		return *b.freeText
+	}
+	if *b.index >= 0 && *b.index < len(b.items) {
+		return b.items[*b.index]
+	}
+	return ""
+}
+
+// setText sets the text of the edit, without it being taken as typed by the user.
+func (b *DropDownStyle) setText(s string) {
+	b.search.SetText(s)
+	b.search.SetCaret(b.search.Len(), 0)
+	b.typed = s
+	b.edited = false
+}
+
+// find makes the list of the items matching the search text q. Items containing q come first,
+// followed by the items with the letters of q in the same order.
+func (b *DropDownStyle) find(q string) {
+	q = strings.TrimSpace(q)
+	b.matches = b.matches[:0]
+	b.found = make(map[int][]int)
+	var fuzzy []int
+	for i, s := range b.items {
+		if q == "" {
+			b.matches = append(b.matches, i)
+			continue
+		}
+		// Headers, separators and disabled items are only listed when nothing is searched for.
+		if !b.selectable(i) {
+			continue
+		}
+		pos, substring := fuzzyMatch(s, q)
+		if pos == nil {
+			continue
+		}
+		b.found[i] = pos
+		if substring {
+			b.matches = append(b.matches, i)
+		} else {
+			fuzzy = append(fuzzy, i)
+		}
+	}
+	b.matches = append(b.matches, fuzzy...)
+	b.matchList.reset()
+	b.matchList.list.Position = layout.Position{}
+	for i := range b.hovered {
+		b.hovered[i] = false
+	}
+	// With free text, Enter keeps the text typed unless an item is picked with the arrow keys.
+	if len(b.matches) > 0 && q != "" && b.freeText == nil {
+		b.hovered[b.matches[0]] = true
+	}
+}
+
+// fuzzyMatch returns the positions of the runes in s that match q, ignoring case, or nil if s does not match.
+// substring is true if q is found in s, else the runes of q are found in the same order with other runes between.
+func fuzzyMatch(s, q string) (pos []int, substring bool) {
+	rs, qs := []rune(s), []rune(q)
+	for i := range rs {
+		rs[i] = unicode.ToLower(rs[i])
+	}
+	for i := range qs {
+		qs[i] = unicode.ToLower(qs[i])
+	}
+	if i := strings.Index(string(rs), string(qs)); i >= 0 {
+		start := len([]rune(string(rs)[:i]))
+		for j := range qs {
+			pos = append(pos, start+j)
+		}
+		return pos, true
+	}
+	j := 0
+	for i, r := range rs {
+		if j < len(qs) && r == qs[j] {
+			pos = append(pos, i)
+			j++
+		}
+	}
+	if j < len(qs) {
+		return nil, false
+	}
+	return pos, false
+}
+
+// hoveredItem returns the item that is highlighted in the list, or -1.
+func (b *DropDownStyle) hoveredItem() int {
+	for _, i := range b.matches {
+		if b.hovered[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveHover highlights the item d lines from the one highlighted, and opens the list if it is closed.
+func (b *DropDownStyle) moveHover(d int) {
+	if !b.Visible {
+		b.Visible = true
+		b.find("")
+	}
+	if len(b.matches) == 0 {
+		return
+	}
+	line := -1
+	for j, i := range b.matches {
+		if b.hovered[i] {
+			line = j
+			b.hovered[i] = false
+		}
+	}
+	if line < 0 && d < 0 {
+		line = len(b.matches)
+	}
+	line = clampInt(line+d, 0, len(b.matches)-1)
+	// Skip the items that can not be selected, in the direction moved, or back if there are none.
+	step := 1
+	if d < 0 {
+		step = -1
+	}
+	for _, dir := range []int{step, -step} {
+		j := line
+		for j >= 0 && j < len(b.matches) && !b.selectable(b.matches[j]) {
+			j += dir
+		}
+		if j >= 0 && j < len(b.matches) {
+			line = j
+			break
+		}
+	}
+	if !b.selectable(b.matches[line]) {
+		return
+	}
+	b.hovered[b.matches[line]] = true
+	b.matchList.scrollTo(line)
+}
+
+// searchKey moves in the list with the arrow keys, PageUp and PageDown, selects with Enter and closes the list with Escape.
+func (b *DropDownStyle) searchKey(k key.Event) bool {
+	switch k.Name {
+	case key.NameDownArrow:
+		b.moveHover(1)
+	case key.NameUpArrow:
+		b.moveHover(-1)
+	case key.NamePageDown:
+		b.moveHover(pageLen(b.matchList))
+	case key.NamePageUp:
+		b.moveHover(-pageLen(b.matchList))
+	case key.NameReturn, key.NameEnter:
+		b.commit()
+	case key.NameEscape:
+		b.Visible = false
+		b.setText(b.value())
+	default:
+		return false
+	}
+	return true
+}
+
+// choose selects item i, and shows it in the edit.
+func (b *DropDownStyle) choose(i int) {
+	if b.search == nil {
+		return
+	}
+	*b.index = i
+	if b.freeText != nil {
+		*b.freeText = b.items[i]
+	}
+	b.setText(b.items[i])
+	b.Visible = false
+}
+
+// commit selects the item highlighted in the list, or the item with the text typed. Else the text is kept
+// as free text if that is allowed, or the first item found is selected. If nothing is found, the text of
+// the selected item is shown again.
+func (b *DropDownStyle) commit() {
+	s := strings.TrimSpace(b.search.Text())
+	if i := b.hoveredItem(); i >= 0 && b.Visible {
+		b.choose(i)
+		return
+	}
+	for i, item := range b.items {
+		if b.selectable(i) && strings.EqualFold(item, s) {
+			b.choose(i)
+			return
+		}
+	}
+	switch {
+	case b.freeText != nil:
+		*b.freeText = s
+		*b.index = -1
+		b.setText(s)
+	case len(b.matches) > 0 && s != "":
+		b.choose(b.matches[0])
+	default:
+		b.setText(b.value())
+	}
+	b.Visible = false
+}
+
+// layoutSearch draws a searchable dropdown, which is an edit with the dropdown icon after it.
+// The list is opened by typing, by the arrow keys or by clicking the icon.
+func (b *DropDownStyle) layoutSearch(gtx C) D {
+	for _, e := range b.iconClick.Events(gtx) {
+		if e.Type == gesture.TypeClick {
+			b.Visible = !b.Visible
+			b.find("")
+			b.search.Focus()
+		}
+	}
+	focused := b.search.Focused()
+	if b.wasFocused && !focused {
+		// Leaving the edit keeps free text, and else shows the selected item again.
+		if b.freeText != nil && b.edited {
+			b.commit()
+		}
+		b.Visible = false
+	}
+	b.wasFocused = focused
+	if !focused && b.search.Text() != b.value() {
+		// The selection was changed by the program.
+		b.setText(b.value())
+	}
+	dims := layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+		layout.Flexed(1, b.search.Layout),
+		layout.Rigid(func(gtx C) D {
+			dims := b.LayoutIcon()(gtx)
+			defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+			b.iconClick.Add(gtx.Ops)
+			pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
+			return dims
+		}),
+	)
+	b.at.layout(gtx, b.th.form, dims.Size)
+	for _, ev := range b.search.Events() {
+		if _, ok := ev.(ChangeEvent); ok && (b.edited || b.search.Text() != b.typed) {
+			b.edited = true
+			b.find(b.search.Text())
+			b.Visible = true
+		}
+	}
+	if b.Visible {
+		layoutPopup(gtx, b.th, dims, b.matchList.Layout, nil, &b.at, b.maxRows*b.optionHeight(gtx))
+	}
+	return dims
+}
+
+// highlight draws item i with the runes matched by the search text in bold, in the primary color.
+func (b *DropDownStyle) highlight(gtx C, i int) D {
+	rs := []rune(b.items[i])
+	matched := make([]bool, len(rs))
+	for _, p := range b.found[i] {
+		if p < len(rs) {
+			matched[p] = true
+		}
+	}
+	var children []layout.FlexChild
+	for start := 0; start < len(rs); {
+		end := start + 1
+		for end < len(rs) && matched[end] == matched[start] {
+			end++
+		}
+		run, bold := string(rs[start:end]), matched[start]
+		children = append(children, layout.Rigid(func(gtx C) D {
+			font, c := text.Font{}, b.th.OnBackground
+			if bold {
+				font.Weight, c = text.Bold, b.th.Primary
+			}
+			paint.ColorOp{Color: c}.Add(gtx.Ops)
+			return aLabel{Alignment: text.Start, MaxLines: 1}.Layout(gtx, b.th.Shaper, font, b.th.TextSize, run)
+		}))
+		start = end
+	}
+	return layout.Flex{}.Layout(gtx, children...)
+}
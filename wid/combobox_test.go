@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		s, q      string
+		pos       []int
+		substring bool
+	}{
+		{"Banana", "nan", []int{2, 3, 4}, true},
+		{"Banana", "BAN", []int{0, 1, 2}, true},
+		{"Blueberry", "bry", []int{0, 6, 8}, false},
+		{"Ærøskøbing", "røs", []int{1, 2, 3}, true},
+		{"Ærøskøbing", "æsb", []int{0, 3, 6}, false},
+		{"Cherry", "yr", nil, false},
+		{"Fig", "figs", nil, false},
+	}
+	for _, test := range tests {
+		pos, substring := fuzzyMatch(test.s, test.q)
+		if !reflect.DeepEqual(pos, test.pos) || substring != test.substring {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", test.s, test.q, pos, substring, test.pos, test.substring)
+		}
+	}
+}
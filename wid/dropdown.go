@@ -2,8 +2,13 @@ package wid
 
 import (
 	"image"
+	"reflect"
+	"strings"
+	"time"
 
 	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/key"
 	"gioui.org/io/pointer"
 	"gioui.org/layout"
 	"gioui.org/op"
@@ -26,15 +31,80 @@ type DropDownStyle struct {
 	Visible    bool
 	wasVisible int
 	list       layout.Widget
+	itemList   *lazyList
 	Items      []layout.Widget
 	icon       *Icon
+	// prefix is the text typed to find an item, and typedAt is when the last key of it was typed.
+	prefix  string
+	typedAt time.Time
+	// search is the edit of a searchable dropdown, nil for a plain one. matches is the items found by the
+	// search text, and found is the positions of the runes matched in each of them.
+	searchable bool
+	search     *EditDef
+	freeText   *string
+	matches    []int
+	found      map[int][]int
+	matchList  *lazyList
+	iconClick  gesture.Click
+	wasFocused bool
+	// typed is the text set by the program, used to tell it from text typed by the user, and edited is
+	// set when the user has changed the text since it was set.
+	typed  string
+	edited bool
+	// at is the position of the box in the window, and maxRows the number of rows shown in the list.
+	at      anchor
+	maxRows int
+	// entries is the items of a dropdown made with DropDownItems, bound the variable with their keys and
+	// keys the key of each item, converted to the type of the variable.
+	// selected is the index of the item selected, and synced the index that was in the variable.
+	entries  []Item
+	bound    reflect.Value
+	keys     []interface{}
+	selected int
+	synced   int
+}
+
+// DropDownOption is options specific to dropdowns.
+type DropDownOption func(*DropDownStyle)
+
+func (o DropDownOption) apply(cfg interface{}) {
+	o(cfg.(*DropDownStyle))
+}
+
+// Searchable is an option parameter that makes the dropdown a combobox, where the closed box is an edit.
+// Typing filters the list to the items that contain the text, or that have its letters in the same order,
+// with the matched letters highlighted. Enter selects the highlighted item, or the first item found.
+func Searchable() DropDownOption {
+	return func(b *DropDownStyle) {
+		b.searchable = true
+	}
+}
+
+// FreeText is an option parameter for a searchable dropdown, that lets the user enter text that is not one
+// of the items. The text is written to s when it is committed with Enter or an item is chosen, and the index
+// is set to -1 if the text is not one of the items.
+func FreeText(s *string) DropDownOption {
+	return func(b *DropDownStyle) {
+		b.searchable = true
+		b.freeText = s
+	}
+}
+
+// MaxRows is an option parameter that sets the number of items shown in the list of a dropdown
+// before it scrolls. The default is 12.
+func MaxRows(n int) DropDownOption {
+	return func(b *DropDownStyle) {
+		b.maxRows = n
+	}
 }
 
 // DropDown returns an initiated struct with drop-dow box setup info
 func DropDown(th *Theme, index *int, items []string, options ...Option) *DropDownStyle {
-	b := DropDownStyle{}
+	return newDropDown(&DropDownStyle{}, th, index, items, options...)
+}
+
+func newDropDown(b *DropDownStyle, th *Theme, index *int, items []string, options ...Option) *DropDownStyle {
 	b.icon, _ = NewIcon(icons.NavigationArrowDropDown)
-	b.SetupTabs()
 	b.th = th
 	b.Font = text.Font{Weight: text.Medium}
 	b.shaper = th.Shaper
@@ -44,12 +114,25 @@ func DropDown(th *Theme, index *int, items []string, options ...Option) *DropDow
 		b.Items = append(b.Items, b.option(th, i))
 		b.hovered = append(b.hovered, false)
 	}
-	b.list = MakeList(th, Overlay, b.Items...)
+	b.itemList = newLazyList(th, Overlay, func() int { return len(b.Items) }, func(i int) layout.Widget { return b.Items[i] })
+	b.list = b.itemList.Layout
 	b.padding = th.LabelPadding
+	b.maxRows = popupRows
 	for _, option := range options {
-		option.apply(&b)
+		option.apply(b)
+	}
+	// A searchable dropdown is reached by tabbing to its edit.
+	if b.searchable {
+		b.makeSearch(th)
+	} else {
+		b.SetupTabs(th.form)
 	}
-	return &b
+	if old, ok := th.form.keep(b.key, b).(*DropDownStyle); ok {
+		b.Clickable.keep(&old.Clickable)
+		b.Visible = old.Visible
+		b.at = old.at
+	}
+	return b
 }
 
 // Layout adds padding to a dropdown box drawn with b.layout().
@@ -70,6 +153,11 @@ func (b *DropDownStyle) layout(gtx C) D {
 		gtx = gtx.Disabled()
 		b.disabled = true
 	}
+	b.load()
+	defer b.store()
+	if b.search != nil {
+		return b.layoutSearch(gtx)
+	}
 	min := CalcMin(gtx, b.width)
 	dims := layout.Stack{Alignment: layout.Center}.Layout(gtx,
 		layout.Expanded(b.LayoutBackground()),
@@ -92,6 +180,8 @@ func (b *DropDownStyle) layout(gtx C) D {
 		),
 	)
 
+	b.at.layout(gtx, b.th.form, dims.Size)
+
 	oldVisible := b.Visible
 	if !b.Focused() {
 		b.Visible = false
@@ -103,24 +193,9 @@ func (b *DropDownStyle) layout(gtx C) D {
 	if b.Visible {
 		if !oldVisible {
 			b.setHovered()
+			b.itemList.scrollTo(max(*b.index, 0))
 		}
-		gtx.Constraints.Min = image.Pt(dims.Size.X, dims.Size.Y)
-		gtx.Constraints.Max.Y = gtx.Constraints.Max.Y - dims.Size.Y - 5
-		macro := op.Record(gtx.Ops)
-		d := b.list(gtx)
-		listClipRect := f32.Rect(0, 0, float32(gtx.Constraints.Min.X), float32(d.Size.Y)) // gtx.Constraints.Min.Y))
-		call := macro.Stop()
-		macro = op.Record(gtx.Ops)
-		op.Offset(f32.Pt(0, float32(dims.Size.Y))).Add(gtx.Ops)
-		stack := clip.UniformRRect(listClipRect, 0).Push(gtx.Ops)
-		paint.Fill(gtx.Ops, b.th.Background)
-		// Draw a border around all options
-		paintBorder(gtx, listClipRect, b.th.OnBackground, b.th.BorderThickness, unit.Value{})
-		call.Add(gtx.Ops)
-		stack.Pop()
-		call = macro.Stop()
-		op.Defer(gtx.Ops, call)
-
+		layoutPopup(gtx, b.th, dims, b.list, nil, &b.at, b.maxRows*b.optionHeight(gtx))
 	} else {
 		b.setHovered()
 	}
@@ -128,7 +203,28 @@ func (b *DropDownStyle) layout(gtx C) D {
 	return dims
 }
 
+// optionHeight returns the height of an item in the list.
+func (b *DropDownStyle) optionHeight(gtx C) int {
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	dims := layout.Inset{Top: unit.Dp(2)}.Layout(gtx, func(gtx C) D {
+		return aLabel{MaxLines: 1}.Layout(gtx, b.th.Shaper, text.Font{}, b.th.TextSize, "Ag")
+	})
+	macro.Stop()
+	return dims.Size.Y
+}
+
 func (b *DropDownStyle) setHovered() {
+	if b.entries != nil {
+		// There may be no selection, and then the first item that can be selected is hovered.
+		for i := range b.hovered {
+			b.hovered[i] = false
+		}
+		if i := b.nearest(*b.index, 1); i >= 0 {
+			b.hovered[i] = true
+		}
+		return
+	}
 	if *b.index >= len(b.hovered) {
 		*b.index = len(b.hovered) - 1
 	}
@@ -147,11 +243,18 @@ func (b *DropDownStyle) option(th *Theme, i int) func(gtx C) D {
 			if e, ok := e.(pointer.Event); ok {
 				switch e.Type {
 				case pointer.Release:
+					if !b.selectable(i) {
+						break
+					}
 					*b.index = i
+					b.choose(i)
 					b.Visible = false
 					b.wasVisible = 0
 					b.hovered[i] = false
 				case pointer.Enter:
+					if !b.selectable(i) {
+						break
+					}
 					for j := 0; j < len(b.hovered); j++ {
 						b.hovered[j] = false
 					}
@@ -173,8 +276,15 @@ func (b *DropDownStyle) option(th *Theme, i int) func(gtx C) D {
 		}
 		paint.ColorOp{Color: th.OnBackground}.Add(gtx.Ops)
 		lblWidget := func(gtx C) D {
+			if b.search != nil && len(b.found[i]) > 0 {
+				return b.highlight(gtx, i)
+			}
 			return aLabel{Alignment: text.Start, MaxLines: 1}.Layout(gtx, th.Shaper, text.Font{}, th.TextSize, b.items[i])
 		}
+		if b.entries != nil {
+			label := lblWidget
+			lblWidget = func(gtx C) D { return b.layoutEntry(gtx, i, label) }
+		}
 		dims := layout.Inset{Top: unit.Dp(2), Left: th.TextSize.Scale(0.4), Right: unit.Dp(0)}.Layout(gtx, lblWidget)
 		defer clip.Rect(image.Rect(0, 0, dims.Size.X, dims.Size.Y)).Push(gtx.Ops).Pop()
 		pointer.InputOp{
@@ -205,7 +315,7 @@ func (b *DropDownStyle) LayoutBackground() func(gtx C) D {
 		oldIndex := *b.index
 		b.LayoutClickable(gtx)
 		b.HandleClicks(gtx)
-		b.HandleKeys(gtx)
+		b.handleKeys(gtx)
 		if *b.index > len(b.hovered) {
 			*b.index = len(b.hovered) - 1
 		}
@@ -217,6 +327,121 @@ func (b *DropDownStyle) LayoutBackground() func(gtx C) D {
 	}
 }
 
+// typeAheadDelay is the time after the last key typed that a new search for an item is started.
+const typeAheadDelay = time.Second
+
+// handleKeys moves the selection with the arrow keys, Home, End, PageUp and PageDown, and to the next item
+// that starts with the text typed. Enter and Space open and close the list, and Escape closes it.
+func (b *DropDownStyle) handleKeys(gtx C) {
+	for _, ev := range gtx.Events(&b.eventKey) {
+		switch ke := ev.(type) {
+		case key.FocusEvent:
+			b.focused = ke.Focus
+		case key.EditEvent:
+			if b.focused {
+				b.typeAhead(gtx, ke.Text)
+			}
+		case key.Event:
+			if b.focused && ke.State == key.Press {
+				b.pressKey(gtx, ke)
+			}
+		}
+	}
+	defer pointer.PassOp{}.Push(gtx.Ops).Pop()
+	key.InputOp{Tag: &b.eventKey}.Add(gtx.Ops)
+	if b.requestFocus {
+		key.FocusOp{Tag: &b.eventKey}.Add(gtx.Ops)
+		key.SoftKeyboardOp{Show: false}.Add(gtx.Ops)
+	}
+	b.requestFocus = false
+}
+
+// pressKey handles the keys that are not text.
+func (b *DropDownStyle) pressKey(gtx C, ke key.Event) {
+	step := 1
+	if ke.Modifiers.Contain(key.ModCtrl) {
+		step = 10
+	}
+	switch ke.Name {
+	case key.NameUpArrow, key.NameLeftArrow:
+		b.moveIndex(-step)
+	case key.NameDownArrow, key.NameRightArrow:
+		b.moveIndex(step)
+	case key.NameHome:
+		b.moveIndex(-len(b.items))
+	case key.NameEnd:
+		b.moveIndex(len(b.items))
+	case key.NamePageUp:
+		b.moveIndex(-pageLen(b.itemList))
+	case key.NamePageDown:
+		b.moveIndex(pageLen(b.itemList))
+	case key.NameReturn, key.NameEnter:
+		b.Visible = !b.Visible
+	case key.NameSpace:
+		// A space typed right after other text is part of the text searched for.
+		if b.prefix == "" || gtx.Now.Sub(b.typedAt) > typeAheadDelay {
+			b.Visible = !b.Visible
+		}
+	case key.NameEscape:
+		b.Visible = false
+	case key.NameTab:
+		if !ke.Modifiers.Contain(key.ModShift) {
+			if b.Next() != nil {
+				b.Next().Focus()
+			}
+		} else if b.Prev() != nil {
+			b.Prev().Focus()
+		}
+	}
+}
+
+// moveIndex moves the selection d items, or to the nearest item that can be selected, and scrolls the list to show it.
+func (b *DropDownStyle) moveIndex(d int) {
+	i := b.nearest(*b.index+d, d)
+	if i < 0 {
+		return
+	}
+	*b.index = i
+	b.itemList.scrollTo(i)
+}
+
+// pageLen returns the number of lines in a page of the list l, which is the lines shown less one.
+func pageLen(l *lazyList) int {
+	if n := l.list.Position.Count - 1; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// typeAhead selects the next item that starts with the text typed. Text typed within typeAheadDelay
+// of the last key is added to the text searched for, and typing the same letter again goes to the
+// next item starting with it, as in the list boxes of most platforms.
+func (b *DropDownStyle) typeAhead(gtx C, s string) {
+	if gtx.Now.Sub(b.typedAt) > typeAheadDelay {
+		b.prefix = ""
+	}
+	b.typedAt = gtx.Now
+	b.prefix += strings.ToLower(s)
+	if strings.TrimSpace(b.prefix) == "" {
+		b.prefix = ""
+		return
+	}
+	prefix, start := b.prefix, *b.index
+	if r := []rune(prefix); len(r) == 1 || strings.Count(prefix, string(r[0])) == len(r) {
+		// A single letter, or the same letter repeated, starts from the item after the selected one.
+		prefix, start = string(r[0]), *b.index+1
+	}
+	n := len(b.items)
+	for k := 0; k < n; k++ {
+		i := ((start+k)%n + n) % n
+		if b.selectable(i) && strings.HasPrefix(strings.ToLower(b.items[i]), prefix) {
+			*b.index = i
+			b.itemList.scrollTo(i)
+			return
+		}
+	}
+}
+
 // LayoutLabel draws the label
 func (b *DropDownStyle) LayoutLabel() layout.Widget {
 	return func(gtx C) D {
@@ -227,6 +452,9 @@ func (b *DropDownStyle) LayoutLabel() layout.Widget {
 		pad := b.th.DropDownPadding
 		pad.Right = unit.Dp(-5)
 		return pad.Layout(gtx, func(gtx C) D {
+			if b.entries != nil {
+				return b.layoutSelected(gtx)
+			}
 			paint.ColorOp{Color: b.th.OnBackground}.Add(gtx.Ops)
 			if *b.index < 0 {
 				*b.index = 0
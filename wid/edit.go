@@ -22,12 +22,24 @@ type EditDef struct {
 	font      text.Font
 	label     string
 	LabelSize unit.Value
+	// value is the variable bound to the text, and text is the value as it was last shown.
+	value      binding
+	text       string
+	wasFocused bool
+	// validators check the text, and err is the error shown under the edit.
+	validators []func(s string) error
+	err        error
 }
 
 // Edit will return a widget (layout function) for a text editor
 func Edit(th *Theme, options ...Option) func(gtx C) D {
+	return newEdit(th, options...).Layout
+}
+
+// newEdit returns an edit, for widgets that need to control the edit after it is made.
+func newEdit(th *Theme, options ...Option) *EditDef {
 	e := new(EditDef)
-	e.SetupTabs()
+	e.SetupTabs(th.form)
 	// Set up default values
 	e.th = th
 	e.shaper = th.Shaper
@@ -39,17 +51,36 @@ func Edit(th *Theme, options ...Option) func(gtx C) D {
 	for _, option := range options {
 		option.apply(e)
 	}
-	return func(gtx C) D {
-		gtx.Constraints.Min.X = 0
-		if e.label == "" {
-			return e.layEdit()(gtx)
-		}
-		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start, Spacing: layout.SpaceStart}.Layout(
-			gtx,
-			layout.Rigid(e.layLabel()),
-			layout.Rigid(e.layEdit()),
-		)
+	if e.value != nil {
+		// Enter will write the text to the bound variable.
+		e.Submit = true
+	}
+	if e.value != nil || len(e.validators) > 0 {
+		th.form.addField(e)
 	}
+	if old, ok := th.form.keep(e.key, e).(*EditDef); ok {
+		e.Editor.keep(&old.Editor)
+		e.text = old.text
+		e.err = old.err
+	} else if e.value != nil {
+		e.text = e.value.format()
+		e.SetText(e.text)
+	}
+	return e
+}
+
+// Layout draws the edit with its label.
+func (e *EditDef) Layout(gtx C) D {
+	e.update()
+	gtx.Constraints.Min.X = 0
+	if e.label == "" {
+		return e.layEdit()(gtx)
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start, Spacing: layout.SpaceStart}.Layout(
+		gtx,
+		layout.Rigid(e.layLabel()),
+		layout.Rigid(e.layEdit()),
+	)
 }
 
 // EditOption is options specific to Edits
@@ -70,6 +101,83 @@ func (e *EditDef) setLabel(s string) {
 	e.label = s
 }
 
+// update synchronizes the text of the editor and the bound variable. Changes made by the user are
+// written to the variable when the text is valid, and changes made by the program are shown.
+// The text is validated when the edit loses focus, and an error is cleared as soon as it is fixed.
+func (e *EditDef) update() {
+	if e.value == nil && len(e.validators) == 0 {
+		return
+	}
+	for _, ev := range e.Editor.Events() {
+		switch ev.(type) {
+		case ChangeEvent:
+			err := e.check(e.Text())
+			if err == nil && e.value != nil {
+				e.text = e.value.format()
+			}
+			if e.err != nil {
+				e.err = err
+			}
+		case SubmitEvent:
+			e.commit()
+		}
+	}
+	if e.wasFocused && !e.Focused() {
+		e.commit()
+	}
+	e.wasFocused = e.Focused()
+	if e.value == nil {
+		return
+	}
+	if s := e.value.format(); s != e.text {
+		e.text = s
+		e.SetText(s)
+	}
+}
+
+// commit validates the text and writes it to the bound variable, and shows the value formatted by the binding.
+func (e *EditDef) commit() {
+	if e.validate() != nil || e.value == nil {
+		return
+	}
+	e.text = e.value.format()
+	if e.Text() != e.text {
+		e.SetText(e.text)
+	}
+}
+
+// check runs the validators on s, and parses s into the bound variable if it is valid.
+func (e *EditDef) check(s string) error {
+	for _, v := range e.validators {
+		if err := v(s); err != nil {
+			return err
+		}
+	}
+	if e.value != nil {
+		return e.value.parse(s)
+	}
+	return nil
+}
+
+// validate checks the text, and keeps the error so that it is shown under the edit.
+func (e *EditDef) validate() error {
+	e.err = e.check(e.Text())
+	return e.err
+}
+
+// Err returns the validation error of the edit, or nil if the text was valid when last checked.
+func (e *EditDef) Err() error {
+	return e.err
+}
+
+// name returns the label of the edit, or the hint if there is no label.
+func (e *EditDef) name() string {
+	if e.label != "" {
+		return e.label
+	}
+	return e.hint
+}
+
 func (e *EditDef) layoutEditBackground() func(gtx C) D {
 	return func(gtx C) D {
 		outline := f32.Rectangle{Max: f32.Point{
@@ -89,21 +197,38 @@ func (e *EditDef) layoutEditBackground() func(gtx C) D {
 func (e *EditDef) layEdit() layout.Widget {
 	return func(gtx C) D {
 		return e.padding.Layout(gtx, func(gtx C) D {
-			return layout.Stack{}.Layout(
+			return layout.Flex{Axis: layout.Vertical}.Layout(
 				gtx,
-				//layout.Expanded(e.layoutEditBackground()),
-				layout.Expanded(func(gtx C) D {
-					gtx.Constraints.Min.X = 5000
-					return e.th.LabelPadding.Layout(gtx, func(gtx C) D {
-						return e.layoutEdit()(gtx)
-					})
+				layout.Rigid(func(gtx C) D {
+					return layout.Stack{}.Layout(
+						gtx,
+						//layout.Expanded(e.layoutEditBackground()),
+						layout.Expanded(func(gtx C) D {
+							gtx.Constraints.Min.X = 5000
+							return e.th.LabelPadding.Layout(gtx, func(gtx C) D {
+								return e.layoutEdit()(gtx)
+							})
+						}),
+						layout.Expanded(layoutBorder(&e.Clickable, e.th, e.err != nil)),
+					)
 				}),
-				layout.Expanded(LayoutBorder(&e.Clickable, e.th)),
+				layout.Rigid(e.layError()),
 			)
 		})
 	}
 }
 
+// layError draws the validation error under the edit, in a smaller font.
+func (e *EditDef) layError() layout.Widget {
+	return func(gtx C) D {
+		if e.err == nil {
+			return D{}
+		}
+		paint.ColorOp{Color: e.th.Error}.Add(gtx.Ops)
+		return aLabel{Alignment: text.Start}.Layout(gtx, e.shaper, e.font, e.th.TextSize.Scale(0.8), e.err.Error())
+	}
+}
+
 func (e *EditDef) layLabel() layout.Widget {
 	return func(gtx C) D {
 		p := e.padding
@@ -83,6 +83,10 @@ type Editor struct {
 
 	//clicker gesture.Click
 
+	// onKey is called with the keys pressed, before the editor handles them. If it returns true,
+	// the key is not handled by the editor. It lets widgets that use an editor take keys like Escape.
+	onKey func(k key.Event) bool
+
 	// events is the list of events not yet processed.
 	events []EditorEvent
 	// prevEvents is the number of events from the previous frame.
@@ -334,6 +338,9 @@ func (e *Editor) processKey(gtx layout.Context) {
 			if !e.Clickable.focused || ke.State != key.Press {
 				break
 			}
+			if e.onKey != nil && e.onKey(ke) {
+				continue
+			}
 			if ke.Name == key.NameTab {
 				if !ke.Modifiers.Contain(key.ModShift) {
 					if e.Next() != nil {
@@ -668,6 +675,16 @@ func (e *Editor) SetText(s string) {
 	e.prepend(s)
 }
 
+// keep takes over the text, caret, selection, scroll position and focus from the editor that e replaces.
+func (e *Editor) keep(old *Editor) {
+	e.Clickable.keep(&old.Clickable)
+	e.rr = old.rr
+	e.caret.start.ofs = old.caret.start.ofs
+	e.caret.end.ofs = old.caret.end.ofs
+	e.scrollOff = old.scrollOff
+	e.invalidate()
+}
+
 func (e *Editor) scrollBounds() image.Rectangle {
 	var b image.Rectangle
 	if e.SingleLine {
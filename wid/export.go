@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gioui.org/layout"
+)
+
+// ExportFormat is the file format used when exporting a table.
+type ExportFormat int
+
+const (
+	// CSV is comma separated values, with the column titles in the first line.
+	CSV ExportFormat = iota
+	// TSV is tab separated values, with the column titles in the first line.
+	TSV
+	// JSON is an array with one object for each row, with the field names of the columns as names.
+	JSON
+)
+
+// ExportFile is an option parameter that sets the file written by the export shortcut (Ctrl-E) and
+// the export button. The format is given by the extension of the file name, .csv, .tsv or .json.
+// The selected rows are written if any rows are selected, else all rows. done is called with the
+// result of the export, and can be nil. A table with a data source fetches all the rows in a goroutine,
+// and writes the file and calls done at the layout after they have arrived.
+func ExportFile(name string, done func(err error)) TableOption {
+	return func(t *TableDef) {
+		t.exportName = name
+		t.exportDone = done
+	}
+}
+
+// ExportButton returns a button that exports the table to the file set by the ExportFile option.
+func (t *TableDef) ExportButton(options ...Option) layout.Widget {
+	options = append([]Option{Handler(t.exportFile)}, options...)
+	return Button(t.th, "Export", options...)
+}
+
+// Export writes the rows of the table to w, in the order they are shown and with the text shown in
+// the cells. Only rows found by the filters are written, also those of collapsed groups, and only the
+// selected ones if selectedOnly is set. Columns without a field are not written. A table with a data
+// source only writes the rows fetched so far. JSON objects use the field names, as titles may repeat.
+func (t *TableDef) Export(w io.Writer, format ExportFormat, selectedOnly bool) error {
+	ids, elem := t.loadedRows()
+	return t.export(w, format, selectedOnly, ids, elem)
+}
+
+// export writes the rows ids, where elem gives the struct of each, to w.
+func (t *TableDef) export(w io.Writer, format ExportFormat, selectedOnly bool, ids []int, elem func(i int) reflect.Value) error {
+	cols := t.exportColumns()
+	rows := t.records(cols, selectedOnly, ids, elem)
+	switch format {
+	case CSV, TSV:
+		cw := csv.NewWriter(w)
+		if format == TSV {
+			cw.Comma = '\t'
+		}
+		titles := make([]string, len(cols))
+		for j, col := range cols {
+			titles[j] = t.columns[col].Title
+		}
+		_ = cw.Write(titles)
+		_ = cw.WriteAll(rows)
+		return cw.Error()
+	case JSON:
+		return writeJSON(w, t.exportNames(cols), rows)
+	}
+	return fmt.Errorf("wid: unknown export format %d", format)
+}
+
+// exportColumns returns the columns with fields, which are the ones exported.
+func (t *TableDef) exportColumns() (cols []int) {
+	for col := range t.columns {
+		if t.fields[col] != nil {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// exportNames returns the names of the columns in JSON objects, which is the field name. A field shown in
+// more than one column gets a number after the name in the following columns, so that the names are unique.
+func (t *TableDef) exportNames(cols []int) []string {
+	names := make([]string, len(cols))
+	used := make(map[string]bool)
+	for j, col := range cols {
+		name := t.columns[col].Field
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", t.columns[col].Field, n)
+		}
+		used[name] = true
+		names[j] = name
+	}
+	return names
+}
+
+// records returns the text of columns cols, for the rows ids, where elem gives the struct of each.
+func (t *TableDef) records(cols []int, selectedOnly bool, ids []int, elem func(i int) reflect.Value) (rows [][]string) {
+	for _, i := range ids {
+		if selectedOnly && !t.sel.Selected(i) {
+			continue
+		}
+		e := elem(i)
+		row := make([]string, len(cols))
+		for j, col := range cols {
+			row[j] = t.text(e, col)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// loadedRows returns the rows found by the filters that can be written without waiting, which for a table
+// with a data source is the rows fetched so far, and the function giving the struct of each.
+func (t *TableDef) loadedRows() ([]int, func(i int) reflect.Value) {
+	if t.pager == nil {
+		return t.foundRows(), t.elem
+	}
+	var ids []int
+	for _, i := range t.view {
+		if t.pager.loaded(i) {
+			ids = append(ids, i)
+		}
+	}
+	return ids, t.elem
+}
+
+// foundRows returns the rows found by the filters in the order they are shown, including the rows of
+// collapsed groups. The rows of a tree table are the ones shown.
+func (t *TableDef) foundRows() []int {
+	if !t.grouped() {
+		return t.view
+	}
+	var rows []int
+	for _, g := range t.groups {
+		rows = append(rows, g.rows...)
+	}
+	return rows
+}
+
+// writeJSON writes the rows as an array of objects, with the names in the same order as the columns.
+func writeJSON(w io.Writer, names []string, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  {")
+		for j, s := range row {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			name, _ := json.Marshal(names[j])
+			value, _ := json.Marshal(s)
+			bw.Write(name)
+			bw.WriteString(": ")
+			bw.Write(value)
+		}
+		bw.WriteString("}")
+	}
+	bw.WriteString("\n]\n")
+	return bw.Flush()
+}
+
+// exportFile writes the table to the file set by the ExportFile option. A table with a data source
+// starts fetching the rows, and the file is written by finishExport when they have arrived.
+func (t *TableDef) exportFile() {
+	if t.exportName == "" {
+		return
+	}
+	if t.pager != nil {
+		t.pager.fetchExport()
+		return
+	}
+	ids, elem := t.loadedRows()
+	err := t.writeFile(t.exportName, ids, elem)
+	if t.exportDone != nil {
+		t.exportDone(err)
+	}
+}
+
+// finishExport writes the file with the rows fetched for the export of a table with a data source,
+// if they have arrived.
+func (t *TableDef) finishExport() {
+	values, ok, err := t.pager.exported()
+	if !ok {
+		return
+	}
+	if err == nil {
+		ids := make([]int, len(values))
+		for i := range ids {
+			ids[i] = i
+		}
+		err = t.writeFile(t.exportName, ids, func(i int) reflect.Value { return values[i] })
+	}
+	if t.exportDone != nil {
+		t.exportDone(err)
+	}
+}
+
+// writeFile writes the rows ids to the file name, in the format given by its extension.
+func (t *TableDef) writeFile(name string, ids []int, elem func(i int) reflect.Value) error {
+	var format ExportFormat
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		format = CSV
+	case ".tsv", ".txt":
+		format = TSV
+	case ".json":
+		format = JSON
+	default:
+		return fmt.Errorf("wid: unknown export file type %s", name)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = t.export(f, format, t.anySelected(), ids, elem)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// anySelected returns true if any of the rows found by the filters are selected.
+func (t *TableDef) anySelected() bool {
+	if t.pager != nil {
+		return t.sel.Len() > 0
+	}
+	for _, i := range t.foundRows() {
+		if t.sel.Selected(i) {
+			return true
+		}
+	}
+	return false
+}
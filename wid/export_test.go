@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fruit struct {
+	Name  string
+	Kind  string
+	Price int
+}
+
+var fruits = []fruit{{"Apple", "Tree", 3}, {"Banana", "Herb", 2}, {"Cherry", "Tree", 5}}
+
+var fruitColumns = []Column{
+	{Title: "Name", Field: "Name"},
+	{Title: "Name", Field: "Name", Format: func(v interface{}) string { return "(" + v.(string) + ")" }},
+	{Title: "Price", Field: "Price"},
+	{Title: "Icon"},
+}
+
+func TestExportCollapsedGroups(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	data := append([]fruit(nil), fruits...)
+	table := Table(th, Occupy, &data, fruitColumns, GroupBy("Kind"))
+	table.collapsed[table.groups[0].label] = true
+	table.Refresh()
+	var b bytes.Buffer
+	if err := table.Export(&b, CSV, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,Name,Price\nBanana,(Banana),2\nApple,(Apple),3\nCherry,(Cherry),5\n"
+	if b.String() != want {
+		t.Errorf("CSV export is\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	data := append([]fruit(nil), fruits[:1]...)
+	table := Table(th, Occupy, &data, fruitColumns)
+	var b bytes.Buffer
+	if err := table.Export(&b, JSON, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "[\n  {\"Name\": \"Apple\", \"Name2\": \"(Apple)\", \"Price\": \"3\"}\n]\n"
+	if b.String() != want {
+		t.Errorf("JSON export is\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestExportSource(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	rows := make([]fruit, pageSize+1)
+	rows[pageSize].Name = "Last"
+	ready := make(chan struct{}, 1)
+	invalidate := func() {
+		select {
+		case ready <- struct{}{}:
+		default:
+		}
+	}
+	name := filepath.Join(t.TempDir(), "fruits.tsv")
+	done := false
+	var data []fruit
+	table := Table(th, Occupy, &data, fruitColumns[:1], Source(NewSliceSource(rows), invalidate),
+		ExportFile(name, func(err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			done = true
+		}))
+	// Export only writes the rows fetched so far, which is none.
+	var b bytes.Buffer
+	if err := table.Export(&b, TSV, false); err != nil || b.String() != "Name\n" {
+		t.Errorf("export of a source with no rows fetched is %q, %v", b.String(), err)
+	}
+	// The export button fetches all rows in a goroutine, and the file is written at a later layout.
+	table.exportFile()
+	for !done {
+		select {
+		case <-ready:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the rows for the export did not arrive")
+		}
+		table.finishExport()
+	}
+	text, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(text, []byte("\n")); n != pageSize+2 {
+		t.Errorf("export of a source has %d lines, want %d", n, pageSize+2)
+	}
+	if !bytes.HasSuffix(text, []byte("\nLast\n")) {
+		t.Errorf("export of a source does not end with the last row")
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gioui.org/layout"
+)
+
+// colFilter is the filter of a table column.
+type colFilter struct {
+	// text must be contained in the formatted field.
+	text string
+	// min and max is the range of a number field. Empty or invalid limits are not used.
+	min string
+	max string
+	// option is the index of the option selected, plus one. Zero selects all rows.
+	option int
+}
+
+// SearchEdit returns an edit for text that is searched for in all columns of the table.
+// Only rows where the text is found in at least one of the columns are shown.
+func (t *TableDef) SearchEdit(options ...Option) layout.Widget {
+	options = append([]Option{Hint("Search"), t.bindFilter(&t.search)}, options...)
+	return Edit(t.th, options...)
+}
+
+// makeFilters returns the row with the filter widgets, or nil if no column has a filter.
+func (t *TableDef) makeFilters() layout.Widget {
+	var cells []layout.Widget
+	found := false
+	if t.selField != nil {
+		cells = append(cells, empty)
+	}
+	for i, c := range t.columns {
+		if !c.Filter || t.fields[i] == nil {
+			cells = append(cells, empty)
+			continue
+		}
+		found = true
+		f := &t.filters[i]
+		// The filter widgets get keys when the table has one, so that they keep their state.
+		keyed := func(name string, options ...Option) []Option {
+			if t.key != "" {
+				options = append(options, Key(t.key+".filter."+c.Field+name))
+			}
+			return options
+		}
+		switch {
+		case len(c.Options) > 0:
+			all := append([]string{"All"}, c.Options...)
+			dd := DropDown(&t.thg, &f.option, all, keyed("")...)
+			cells = append(cells, func(gtx C) D {
+				option := f.option
+				dims := dd.Layout(gtx)
+				if f.option != option {
+					t.dirty = true
+				}
+				return dims
+			})
+		case isNumber(t.fieldKind(i)):
+			min := Edit(&t.thg, keyed(".min", Hint("Min"), t.bindFilter(&f.min))...)
+			max := Edit(&t.thg, keyed(".max", Hint("Max"), t.bindFilter(&f.max))...)
+			cells = append(cells, func(gtx C) D {
+				return layout.Flex{}.Layout(gtx, layout.Flexed(0.5, min), layout.Flexed(0.5, max))
+			})
+		default:
+			cells = append(cells, Edit(&t.thg, keyed("", Hint("Filter"), t.bindFilter(&f.text))...))
+		}
+	}
+	if !found || t.pager != nil {
+		return nil
+	}
+	return t.tableRow(&t.thh, nil, cells...)
+}
+
+// empty is a widget that draws nothing.
+func empty(gtx C) D {
+	return D{}
+}
+
+// fieldKind returns the kind of the field in column col.
+func (t *TableDef) fieldKind(col int) reflect.Kind {
+	typ := t.data.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.FieldByIndex(t.fields[col]).Type.Kind()
+}
+
+// filterBinding binds the text of a filter edit, and marks the filters as changed when the text is edited.
+type filterBinding struct {
+	stringBinding
+	dirty *bool
+}
+
+func (b filterBinding) parse(s string) error {
+	if s != *b.v {
+		*b.dirty = true
+	}
+	return b.stringBinding.parse(s)
+}
+
+// bindFilter is an option parameter that binds the text of an edit to the filter text s of the table.
+func (t *TableDef) bindFilter(s *string) EditOption {
+	return func(e *EditDef) {
+		e.value = filterBinding{stringBinding: stringBinding{v: s}, dirty: &t.dirty}
+	}
+}
+
+// match returns true if the element e is found by the search text and all column filters.
+func (t *TableDef) match(e reflect.Value) bool {
+	if s := strings.ToLower(strings.TrimSpace(t.search)); s != "" {
+		found := false
+		for col := range t.columns {
+			if t.fields[col] != nil && strings.Contains(strings.ToLower(t.text(e, col)), s) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for col, f := range t.filters {
+		if !t.columns[col].Filter || t.fields[col] == nil {
+			continue
+		}
+		v := e.FieldByIndex(t.fields[col])
+		switch {
+		case len(t.columns[col].Options) > 0:
+			if f.option > 0 && isInteger(v.Kind()) && intValue(v) != int64(f.option-1) {
+				return false
+			}
+		case isNumber(v.Kind()):
+			x := toFloat(v)
+			if min, err := strconv.ParseFloat(strings.TrimSpace(f.min), 64); err == nil && x < min {
+				return false
+			}
+			if max, err := strconv.ParseFloat(strings.TrimSpace(f.max), 64); err == nil && x > max {
+				return false
+			}
+		default:
+			s := strings.ToLower(strings.TrimSpace(f.text))
+			if s != "" && !strings.Contains(strings.ToLower(t.text(e, col)), s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isNumber returns true for integer and float kinds.
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// toFloat returns the value of a number as a float.
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
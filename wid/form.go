@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+)
+
+// Form owns the widget tree of a window or a dialog, its tab sequence and its theme.
+// Widgets are registered with the form of the theme they are created with, so each
+// window or form should have its own theme.
+type Form struct {
+	th    *Theme
+	root  layout.Widget
+	first Focuser
+	prev  Focuser
+	// state is the keyed widgets of the current widget tree, and old is the ones from the
+	// previous tree, which rebuilt widgets can take over the state from.
+	state map[string]interface{}
+	old   map[string]interface{}
+	// refocus is set when a rebuilt widget takes over the focus.
+	refocus bool
+	// scope is the key prefix of the widgets being built by scoped, and seq numbers the widgets without a key.
+	scope string
+	seq   int
+	// fields is the widgets that are checked by Validate, in tab sequence, and detached is set while
+	// widgets are made by detach, which are not added to fields.
+	fields   []validator
+	detached int
+	// size is the size of the window, and pointer is the last pointer position in it, used to place popups.
+	size    image.Point
+	pointer f32.Point
+}
+
+// NewForm returns a new form using the theme th.
+func NewForm(th *Theme) *Form {
+	f := &Form{}
+	f.SetTheme(th)
+	return f
+}
+
+// SetTheme changes the theme of the form. Widgets created with th, or with a copy of th made
+// after SetTheme, will belong to the form. The previous theme no longer belongs to the form.
+// It panics if th, or the theme it is a copy of, belongs to another form, since the widgets
+// would silently join the tab sequence of that form.
+func (f *Form) SetTheme(th *Theme) {
+	if th != nil && th.form != nil && th.form != f {
+		panic("wid: the theme belongs to another form, each form needs a theme of its own")
+	}
+	if f.th != nil && f.th != th && f.th.form == f {
+		f.th.form = nil
+	}
+	f.th = th
+	if th != nil {
+		th.form = f
+	}
+}
+
+// Theme returns the theme of the form.
+func (f *Form) Theme() *Theme {
+	return f.th
+}
+
+// Init will drop the old widget tree, and must be called before the widgets are made.
+// Keyed widget state is kept, so that the rebuilt widgets can take it over.
+func (f *Form) Init() {
+	f.root = nil
+	f.first = nil
+	f.prev = nil
+	f.old = f.state
+	f.state = make(map[string]interface{})
+	f.refocus = false
+	f.fields = nil
+}
+
+// Setup will set the root widget (usually a list), and focus the first widget
+// unless a rebuilt widget has taken over the focus.
+func (f *Form) Setup(w layout.Widget) {
+	f.root = w
+	// Focus the component to be focused at startup.
+	if f.first != nil && !f.refocus {
+		f.first.Focus()
+	}
+}
+
+// Root returns the root widget of the form, or nil if it is not set up.
+func (f *Form) Root() layout.Widget {
+	return f.root
+}
+
+// First returns the first widget in the tab sequence.
+func (f *Form) First() Focuser {
+	return f.first
+}
+
+// Layout draws the widget tree of the form, which fills the window.
+func (f *Form) Layout(gtx C) D {
+	if f.root == nil {
+		return D{}
+	}
+	f.size = gtx.Constraints.Max
+	for _, e := range gtx.Events(&f.pointer) {
+		if e, ok := e.(pointer.Event); ok {
+			f.pointer = e.Position
+		}
+	}
+	dims := f.root(gtx)
+	trackPointer(gtx, &f.pointer)
+	return dims
+}
+
+// Validate checks all fields of the form, and returns the errors found, prefixed with the
+// field labels. The first invalid field is focused. Nil is returned when all fields are valid.
+// Edits made while the form is running, like those in the rows of a lazy list or the filters
+// of a table, are not checked.
+func (f *Form) Validate() []error {
+	var errs []error
+	for _, w := range f.fields {
+		err := w.validate()
+		if err == nil {
+			continue
+		}
+		if len(errs) == 0 {
+			w.Focus()
+		}
+		if w.name() != "" {
+			err = fmt.Errorf("%s: %w", w.name(), err)
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// addField adds w to the fields checked by Validate, unless it is made by detach. Such widgets are made
+// again while the form is running, and the fields would pile up with widgets that are no longer shown.
+func (f *Form) addField(w validator) {
+	if f != nil && f.detached == 0 {
+		f.fields = append(f.fields, w)
+	}
+}
+
+// add links w into the tab sequence, after the previously added widget.
+func (f *Form) add(w Focuser) {
+	if f.first == nil {
+		f.first = w
+	}
+	if f.prev != nil {
+		w.SetPrev(f.prev)
+		f.prev.SetNext(w)
+	}
+	f.prev = w
+}
+
+// keep stores the widget w under key, and returns the widget that had the same key in the
+// previous widget tree, or nil. The caller takes over the state of the returned widget.
+// Inside scoped, the key is prefixed with the scope, and widgets without a key get one from their
+// order in the scope, so that all widgets of a rebuilt list row take over their state.
+func (f *Form) keep(key string, w interface{}) interface{} {
+	if f != nil && f.scope != "" {
+		if key == "" {
+			f.seq++
+			key = fmt.Sprintf("#%d", f.seq)
+		}
+		key = f.scope + "/" + key
+	}
+	if f == nil || key == "" {
+		return nil
+	}
+	// Widgets built while the form is running may replace a widget from the current tree.
+	old, ok := f.state[key]
+	if !ok {
+		old = f.old[key]
+	}
+	if f.state == nil {
+		f.state = make(map[string]interface{})
+	}
+	f.state[key] = w
+	if fw, ok := old.(interface{ Focused() bool }); ok && fw.Focused() {
+		f.refocus = true
+	}
+	return old
+}
+
+// detach calls build with an empty tab sequence, so that the widgets made by build are linked
+// only to each other. It is used for widgets made while the form is running.
+func (f *Form) detach(build func()) {
+	if f == nil {
+		build()
+		return
+	}
+	prev := f.prev
+	f.prev = nil
+	f.detached++
+	build()
+	f.detached--
+	f.prev = prev
+}
+
+// scoped calls build detached, with the keys of the widgets made by build prefixed with scope.
+func (f *Form) scoped(scope string, build func()) {
+	if f == nil {
+		build()
+		return
+	}
+	oldScope, oldSeq := f.scope, f.seq
+	f.scope, f.seq = scope, 0
+	f.detach(build)
+	f.scope, f.seq = oldScope, oldSeq
+}
+
+// drop forgets the state of the widgets built in scope, except a focused widget, which keeps
+// the focus when it is built again.
+func (f *Form) drop(scope string) {
+	if f == nil {
+		return
+	}
+	prefix := scope + "/"
+	for _, m := range []map[string]interface{}{f.state, f.old} {
+		for key, w := range m {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if fw, ok := w.(interface{ Focused() bool }); ok && fw.Focused() {
+				continue
+			}
+			delete(m, key)
+		}
+	}
+}
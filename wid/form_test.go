@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import "testing"
+
+func TestScopedKeep(t *testing.T) {
+	f := &Form{}
+	a, b := new(int), new(int)
+	f.scoped("list/1", func() {
+		if old := f.keep("", a); old != nil {
+			t.Errorf("first build took over %v", old)
+		}
+		f.keep("name", b)
+	})
+	f.scoped("list/1", func() {
+		if old := f.keep("", new(int)); old != a {
+			t.Errorf("unkeyed widget took over %v, want %v", old, a)
+		}
+		if old := f.keep("name", new(int)); old != b {
+			t.Errorf("keyed widget took over %v, want %v", old, b)
+		}
+	})
+	if old := f.keep("", a); old != nil {
+		t.Errorf("unkeyed widget outside a scope took over %v", old)
+	}
+	f.drop("list/1")
+	if len(f.state) != 0 {
+		t.Errorf("drop left %d widgets", len(f.state))
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	light, dark := &Theme{}, &Theme{}
+	f := NewForm(light)
+	f.SetTheme(dark)
+	if light.Form() != nil || dark.Form() != f {
+		t.Errorf("forms of themes are %p and %p, want nil and %p", light.Form(), dark.Form(), f)
+	}
+	copied := *dark
+	if copied.Form() != f {
+		t.Errorf("copy of theme belongs to %p, want %p", copied.Form(), f)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("giving a theme of one form to another did not panic")
+		}
+	}()
+	NewForm(&copied)
+}
@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gioui.org/gesture"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Aggregate is a value computed from the numbers in a column, shown in the group footers and the total footer.
+// The numbers are kept until the rows they are computed from change, so when the program changes the data it
+// must call Changed for the elements changed, or Refresh. Edits and pastes in the table are handled by the table.
+type Aggregate int
+
+const (
+	// NoAggregate is the default, with nothing shown in the footers.
+	NoAggregate Aggregate = iota
+	// Sum is the sum of the numbers.
+	Sum
+	// Minimum is the smallest number.
+	Minimum
+	// Maximum is the largest number.
+	Maximum
+	// Average is the average of the numbers.
+	Average
+)
+
+// group is the rows with the same value in the group field.
+type group struct {
+	value reflect.Value
+	label string
+	rows  []int
+	// stats is the numbers for the aggregates of each column, nil when they must be computed again.
+	stats []stats
+}
+
+// stats is the numbers needed for the aggregates of a column.
+type stats struct {
+	n        int
+	sum      float64
+	min, max float64
+}
+
+// lineKind is the kind of a line in the list of a grouped table.
+type lineKind int
+
+const (
+	groupLine lineKind = iota
+	dataLine
+	footerLine
+)
+
+// listLine is a line in the list of a grouped table. Index is the row in the view for data lines, else the group.
+type listLine struct {
+	kind  lineKind
+	index int
+}
+
+// GroupBy is an option parameter that groups the rows on the value of a field. Each group gets a header with
+// the value and the number of rows, which can be clicked to collapse the group. If any column has an Aggregate,
+// each group also gets a footer with the aggregates of its rows. Grouping is not used for tree tables.
+func GroupBy(field string) TableOption {
+	return func(t *TableDef) {
+		typ := t.data.Type().Elem()
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		t.groupField = fieldIndex(typ, field)
+	}
+}
+
+// Changed tells the table that element i of the data was changed by the program, so that the aggregates
+// of its group are computed again. If the group field was changed, the rows are grouped again.
+// Changes made by editing or pasting in the table are handled without calling Changed.
+func (t *TableDef) Changed(i int) {
+	t.totals = nil
+	g, ok := t.groupOf[i]
+	if !ok {
+		return
+	}
+	t.groups[g].stats = nil
+	if t.groupLabel(t.elem(i)) != t.groups[g].label {
+		t.later(t.Refresh)
+	}
+}
+
+// grouped returns true if the rows are shown in groups.
+func (t *TableDef) grouped() bool {
+	return t.groupField != nil && t.nodes == nil && t.pager == nil
+}
+
+// makeGroups puts the rows of the view into groups, ordered on the group value.
+func (t *TableDef) makeGroups() {
+	t.groups = t.groups[:0]
+	t.groupOf = make(map[int]int)
+	index := make(map[string]int)
+	for _, id := range t.view {
+		e := t.elem(id)
+		label := t.groupLabel(e)
+		g, ok := index[label]
+		if !ok {
+			g = len(t.groups)
+			index[label] = g
+			t.groups = append(t.groups, group{value: e.FieldByIndex(t.groupField), label: label})
+		}
+		t.groups[g].rows = append(t.groups[g].rows, id)
+	}
+	sort.SliceStable(t.groups, func(i, j int) bool { return less(t.groups[i].value, t.groups[j].value) })
+	for g := range t.groups {
+		for _, id := range t.groups[g].rows {
+			t.groupOf[id] = g
+		}
+	}
+	t.totals = nil
+}
+
+// groupLabel returns the text for the group value of the struct e, formatted like the column showing
+// the field, if there is one.
+func (t *TableDef) groupLabel(e reflect.Value) string {
+	for col := range t.columns {
+		if reflect.DeepEqual(t.fields[col], t.groupField) {
+			return t.columns[col].Title + ": " + t.text(e, col)
+		}
+	}
+	return fmt.Sprint(e.FieldByIndex(t.groupField).Interface())
+}
+
+// arrange makes the view and the lines of the list from the groups, without the rows of collapsed groups.
+func (t *TableDef) arrange() {
+	t.view = t.view[:0]
+	t.lines = t.lines[:0]
+	for g := range t.groups {
+		t.lines = append(t.lines, listLine{kind: groupLine, index: g})
+		if t.collapsed[t.groups[g].label] {
+			continue
+		}
+		for _, id := range t.groups[g].rows {
+			t.lines = append(t.lines, listLine{kind: dataLine, index: len(t.view)})
+			t.view = append(t.view, id)
+		}
+		if t.aggregated() {
+			t.lines = append(t.lines, listLine{kind: footerLine, index: g})
+		}
+	}
+}
+
+// aggregated returns true if any column has an aggregate.
+func (t *TableDef) aggregated() bool {
+	for col, c := range t.columns {
+		if c.Aggregate != NoAggregate && t.fields[col] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// groupStats returns the numbers for the aggregates of group g, or of all rows found by the filters if g is -1.
+// The totals are made from the numbers of the groups, so only the groups that changed are computed again.
+func (t *TableDef) groupStats(g int) []stats {
+	if g >= 0 {
+		if t.groups[g].stats == nil {
+			t.groups[g].stats = t.stats(t.groups[g].rows)
+		}
+		return t.groups[g].stats
+	}
+	if t.totals != nil {
+		return t.totals
+	}
+	if !t.grouped() {
+		t.totals = t.stats(t.view)
+		return t.totals
+	}
+	t.totals = make([]stats, len(t.columns))
+	for g := range t.groups {
+		for col, s := range t.groupStats(g) {
+			t.totals[col].add(s)
+		}
+	}
+	return t.totals
+}
+
+// stats returns the numbers for the aggregates of each column, for the rows given.
+func (t *TableDef) stats(rows []int) []stats {
+	st := make([]stats, len(t.columns))
+	for col, c := range t.columns {
+		if c.Aggregate == NoAggregate || t.fields[col] == nil {
+			continue
+		}
+		for _, id := range rows {
+			v := t.elem(id).FieldByIndex(t.fields[col])
+			if isNumber(v.Kind()) {
+				x := toFloat(v)
+				st[col].add(stats{n: 1, sum: x, min: x, max: x})
+			}
+		}
+	}
+	return st
+}
+
+// add includes the numbers in b.
+func (s *stats) add(b stats) {
+	if b.n == 0 {
+		return
+	}
+	if s.n == 0 || b.min < s.min {
+		s.min = b.min
+	}
+	if s.n == 0 || b.max > s.max {
+		s.max = b.max
+	}
+	s.n += b.n
+	s.sum += b.sum
+}
+
+// aggregateText returns the text shown for the aggregate of column col in group g, or in the total footer if g is -1.
+func (t *TableDef) aggregateText(g, col int) string {
+	s := t.groupStats(g)[col]
+	if s.n == 0 {
+		return ""
+	}
+	var v float64
+	name := ""
+	switch t.columns[col].Aggregate {
+	case Sum:
+		v, name = s.sum, "Sum"
+	case Minimum:
+		v, name = s.min, "Min"
+	case Maximum:
+		v, name = s.max, "Max"
+	case Average:
+		v, name = math.Round(s.sum/float64(s.n)*100)/100, "Avg"
+	default:
+		return ""
+	}
+	return name + " " + strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// footer returns the row with the aggregates of group g, or the total footer if g is -1.
+func (t *TableDef) footer(g int) layout.Widget {
+	var cells []layout.Widget
+	if t.selField != nil {
+		cells = append(cells, empty)
+	}
+	for col := range t.columns {
+		col := col
+		cells = append(cells, Value(&t.thh, func() string { return t.aggregateText(g, col) }))
+	}
+	if g < 0 {
+		return Col(Separator(t.th, t.th.BorderThickness, W(9999)), t.tableRow(&t.thh, nil, cells...))
+	}
+	return Col(t.tableRow(&t.thh, nil, cells...), Separator(t.th, t.th.BorderThickness, W(9999)))
+}
+
+// layoutFooter draws the total footer under the list, following the horizontal scrolling of the list.
+func (t *TableDef) layoutFooter(gtx C) D {
+	if !t.aggregated() {
+		return D{}
+	}
+	if t.totalRow == nil {
+		t.totalRow = t.footer(-1)
+	}
+	macro := op.Record(gtx.Ops)
+	dims := t.totalRow(gtx)
+	call := macro.Stop()
+	dims.Size.X = gtx.Constraints.Max.X
+	defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+	defer op.Offset(layout.FPt(image.Pt(-t.list.Hpos, 0))).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+	return dims
+}
+
+// groupHeader returns the header of group g, which collapses or expands the group when clicked.
+func (t *TableDef) groupHeader(g int) layout.Widget {
+	var click gesture.Click
+	label := t.groups[g].label
+	title := Value(&t.thh, func() string { return fmt.Sprintf("%s (%d)", label, len(t.groups[g].rows)) }, Bold())
+	return func(gtx C) D {
+		for _, e := range click.Events(gtx) {
+			if e.Type == gesture.TypeClick {
+				t.later(func() {
+					t.collapsed[label] = !t.collapsed[label]
+					t.sort()
+					t.curRow = clampInt(t.curRow, 0, len(t.view)-1)
+				})
+			}
+		}
+		width := 0
+		for _, w := range t.widths {
+			width += w
+		}
+		gtx.Constraints.Min.X, gtx.Constraints.Max.X = width, width
+		size := gtx.Px(t.th.TextSize.Scale(1.2))
+		macro := op.Record(gtx.Ops)
+		dims := layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				icon := t.collapseIcon
+				if t.collapsed[label] {
+					icon = t.expandIcon
+				}
+				gtx.Constraints = layout.Exact(image.Pt(size, size))
+				return icon.Layout(gtx, t.thh.OnBackground)
+			}),
+			layout.Flexed(1, title),
+		)
+		call := macro.Stop()
+		defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+		paint.Fill(gtx.Ops, t.thh.Background)
+		call.Add(gtx.Ops)
+		click.Add(gtx.Ops)
+		pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
+		return dims
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import "testing"
+
+func TestAggregates(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	data := []stock{{"Apple", "Tree", 3, 0, false}, {"Banana", "Herb", 2, 0, false}, {"Cherry", "Tree", 5, 0, false}}
+	columns := []Column{{Title: "Name", Field: "Name"}, {Title: "Count", Field: "Count", Aggregate: Sum}}
+	sum := func(table *TableDef, g int) float64 { return table.groupStats(g)[1].sum }
+
+	table := Table(th, Occupy, &data, columns)
+	if s := sum(table, -1); s != 10 {
+		t.Errorf("sum is %v, want 10", s)
+	}
+	data[0].Count = 4
+	table.Refresh()
+	if s := sum(table, -1); s != 11 {
+		t.Errorf("sum after Refresh is %v, want 11", s)
+	}
+
+	table = Table(th, Occupy, &data, columns, GroupBy("Kind"))
+	if s, g := sum(table, -1), sum(table, 1); s != 11 || g != 9 {
+		t.Errorf("sums are %v and %v, want 11 and 9", s, g)
+	}
+	data[2].Count = 1
+	table.Changed(2)
+	if s, g := sum(table, -1), sum(table, 1); s != 7 || g != 5 {
+		t.Errorf("sums after Changed are %v and %v, want 7 and 5", s, g)
+	}
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+)
+
+// Item is an entry in the list of a dropdown made with DropDownItems.
+type Item struct {
+	// Key is the value of the item, that the dropdown is bound to. It must be comparable, and convertible
+	// to the type of the variable. A nil key is the zero value of the variable.
+	Key interface{}
+	// Text is shown for the item, and is what is searched and typed ahead.
+	Text string
+	// Icon is shown before the text, if it is not nil.
+	Icon *Icon
+	// Secondary is shown after the text, in a lighter color, like a shortcut or a count.
+	Secondary string
+	// Disabled items are shown, but can not be selected.
+	Disabled bool
+	// Header makes the item the title of the group of items following it. It can not be selected.
+	Header bool
+	// Separator makes the item a line between groups of items. It can not be selected.
+	Separator bool
+}
+
+// DropDownItems returns a dropdown with the items given, bound to value, which is a pointer to a variable
+// of the type of the item keys. The item with the key equal to the variable is selected, so the items can
+// be reordered, inserted or removed without changing the selection. If no item has the key, nothing is
+// selected, and the variable is only changed when the user selects an item.
+// It panics if a key can not be converted to the type of the variable, or can not be compared.
+func DropDownItems(th *Theme, value interface{}, items []Item, options ...Option) *DropDownStyle {
+	var texts []string
+	for _, item := range items {
+		texts = append(texts, item.Text)
+	}
+	b := &DropDownStyle{entries: items, bound: reflect.ValueOf(value).Elem(), selected: -1, synced: -1}
+	b.keys = itemKeys(items, b.bound.Type())
+	return newDropDown(b, th, &b.selected, texts, options...)
+}
+
+// itemKeys returns the keys of the items converted to typ, so that they can be compared with the variable.
+func itemKeys(items []Item, typ reflect.Type) []interface{} {
+	if !typ.Comparable() {
+		panic("wid: the variable of a dropdown with items must be comparable, not " + typ.String())
+	}
+	keys := make([]interface{}, len(items))
+	for i, item := range items {
+		if item.Key == nil {
+			keys[i] = reflect.Zero(typ).Interface()
+			continue
+		}
+		k := reflect.ValueOf(item.Key)
+		// Converting an integer to a string gives the character with that code, which is not what is meant.
+		if !k.Type().Comparable() || !k.Type().ConvertibleTo(typ) || (isInteger(k.Kind()) && typ.Kind() == reflect.String) {
+			panic(fmt.Sprintf("wid: the key of item %q is a %s, which can not be compared with a %s", item.Text, k.Type(), typ))
+		}
+		keys[i] = k.Convert(typ).Interface()
+	}
+	return keys
+}
+
+// selectable returns true if item i can be selected.
+func (b *DropDownStyle) selectable(i int) bool {
+	if i < 0 || i >= len(b.items) {
+		return false
+	}
+	if b.entries == nil {
+		return true
+	}
+	e := b.entries[i]
+	return !e.Disabled && !e.Header && !e.Separator
+}
+
+// nearest returns the first item from i, in the direction of d, that can be selected. If there is none,
+// the nearest one in the other direction is returned, or -1 if no item can be selected.
+func (b *DropDownStyle) nearest(i, d int) int {
+	if d >= 0 {
+		d = 1
+	} else {
+		d = -1
+	}
+	i = clampInt(i, 0, len(b.items)-1)
+	for _, dir := range []int{d, -d} {
+		for j := i; j >= 0 && j < len(b.items); j += dir {
+			if b.selectable(j) {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// load selects the item with the key in the bound variable, or nothing if no item has it.
+func (b *DropDownStyle) load() {
+	if b.entries == nil {
+		return
+	}
+	b.selected = -1
+	v := b.bound.Interface()
+	for i, k := range b.keys {
+		if k == v {
+			b.selected = i
+			break
+		}
+	}
+	b.synced = b.selected
+}
+
+// store writes the key of the item selected by the user to the bound variable.
+func (b *DropDownStyle) store() {
+	if b.entries == nil || b.selected == b.synced || !b.selectable(b.selected) {
+		return
+	}
+	if k := b.keys[b.selected]; k == nil {
+		// The zero value of an interface variable.
+		b.bound.Set(reflect.Zero(b.bound.Type()))
+	} else {
+		b.bound.Set(reflect.ValueOf(k))
+	}
+	b.synced = b.selected
+}
+
+// layoutEntry draws item i in the list, with label drawing its text.
+func (b *DropDownStyle) layoutEntry(gtx C, i int, label layout.Widget) D {
+	e := b.entries[i]
+	switch {
+	case e.Separator:
+		h := gtx.Px(b.th.TextSize.Scale(0.5))
+		line := image.Rect(0, h/2, gtx.Constraints.Max.X, h/2+max(gtx.Px(unit.Dp(1)), 1))
+		paint.FillShape(gtx.Ops, MulAlpha(b.th.OnBackground, 80), clip.Rect(line).Op())
+		return D{Size: image.Pt(gtx.Constraints.Min.X, h)}
+	case e.Header:
+		paint.ColorOp{Color: MulAlpha(b.th.OnBackground, 160)}.Add(gtx.Ops)
+		return aLabel{Alignment: text.Start, MaxLines: 1}.Layout(gtx, b.th.Shaper, text.Font{Weight: text.Bold}, b.th.TextSize.Scale(0.85), e.Text)
+	}
+	fg := b.th.OnBackground
+	if e.Disabled {
+		fg = Disabled(fg)
+	}
+	return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(b.entryIcon(e, fg)),
+		layout.Flexed(1, func(gtx C) D {
+			paint.ColorOp{Color: fg}.Add(gtx.Ops)
+			return label(gtx)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if e.Secondary == "" {
+				return D{}
+			}
+			paint.ColorOp{Color: MulAlpha(fg, 140)}.Add(gtx.Ops)
+			return layout.Inset{Left: b.th.TextSize.Scale(0.5), Right: b.th.TextSize.Scale(0.4)}.Layout(gtx, func(gtx C) D {
+				return aLabel{MaxLines: 1}.Layout(gtx, b.th.Shaper, text.Font{}, b.th.TextSize.Scale(0.85), e.Secondary)
+			})
+		}),
+	)
+}
+
+// entryIcon returns a widget drawing the icon of an item, followed by a gap, or nothing if it has no icon.
+func (b *DropDownStyle) entryIcon(e Item, fg color.NRGBA) layout.Widget {
+	return func(gtx C) D {
+		if e.Icon == nil {
+			return D{}
+		}
+		size := gtx.Px(b.th.TextSize)
+		return layout.Inset{Right: b.th.TextSize.Scale(0.4)}.Layout(gtx, func(gtx C) D {
+			gtx.Constraints = layout.Exact(image.Pt(size, size))
+			return e.Icon.Layout(gtx, fg)
+		})
+	}
+}
+
+// layoutSelected draws the item selected in the closed box, with its icon.
+func (b *DropDownStyle) layoutSelected(gtx C) D {
+	if b.selected < 0 || b.selected >= len(b.entries) {
+		return D{Size: image.Pt(gtx.Constraints.Min.X, gtx.Px(b.th.TextSize))}
+	}
+	e := b.entries[b.selected]
+	return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(b.entryIcon(e, b.th.OnBackground)),
+		layout.Flexed(1, func(gtx C) D {
+			paint.ColorOp{Color: b.th.OnBackground}.Add(gtx.Ops)
+			return aLabel{Alignment: text.Start, MaxLines: 1}.Layout(gtx, b.shaper, b.Font, b.th.TextSize, e.Text)
+		}),
+	)
+}
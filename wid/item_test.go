@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import "testing"
+
+func TestDropDownItemKeys(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	var size int64 = 2
+	items := []Item{{Text: "Sizes", Header: true}, {Key: 1, Text: "Small"}, {Key: 2, Text: "Large"}}
+	b := DropDownItems(th, &size, items)
+	b.load()
+	if b.selected != 2 {
+		t.Errorf("the variable selects item %d, want 2", b.selected)
+	}
+	b.selected = 1
+	b.store()
+	if size != 1 {
+		t.Errorf("the variable is %d after the first item is selected, want 1", size)
+	}
+
+	var fruit interface{}
+	b = DropDownItems(th, &fruit, []Item{{Text: "None"}, {Key: "apple", Text: "Apple"}})
+	b.load()
+	if b.selected != 0 {
+		t.Errorf("a nil variable selects item %d, want 0", b.selected)
+	}
+
+	for _, test := range []struct {
+		name  string
+		value interface{}
+		items []Item
+	}{
+		{"string key for an int", &size, []Item{{Key: "1"}}},
+		{"int key for a string", new(string), []Item{{Key: 65}}},
+		{"slice variable", new([]int), []Item{{Key: []int{1}}}},
+		{"slice key", &fruit, []Item{{Key: []int{1}}}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", test.name)
+				}
+			}()
+			DropDownItems(th, test.value, test.items)
+		}()
+	}
+}
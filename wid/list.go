@@ -3,9 +3,11 @@
 package wid
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
+	"strconv"
 
 	"gioui.org/f32"
 	"gioui.org/io/pointer"
@@ -22,6 +24,10 @@ import (
 // in order to do this. The returned values will be in the range [0,1], and
 // start will be less than or equal to end.
 func fromListPosition(lp layout.Position, elements int, majorAxisSize int) (start, end float32) {
+	if elements == 0 || lp.Length == 0 {
+		// Nothing to scroll, the viewport covers all the content.
+		return 0, 1
+	}
 	// Approximate the size of the scrollable content.
 	lengthPx := float32(lp.Length)
 	meanElementHeight := lengthPx / float32(elements)
@@ -232,6 +238,7 @@ const (
 // ListStyle configures the presentation of a layout.List with a scrollbar.
 type ListStyle struct {
 	list       *layout.List
+	key        string
 	Hpos       int
 	VScrollBar ScrollbarStyle
 	HScrollBar ScrollbarStyle
@@ -267,15 +274,46 @@ func calcWidths(gtx C, textSize unit.Value, weights []float32, widths []int) {
 	}
 }
 
+// scrollTo scrolls the list so that line i is visible, with a line after it when it is at the bottom.
+func (l *ListStyle) scrollTo(i int) {
+	pos := &l.list.Position
+	if i <= pos.First {
+		pos.First = i
+		pos.Offset = 0
+	} else if i >= pos.First+pos.Count-1 {
+		pos.First = clampInt(i+2-pos.Count, 0, i)
+		pos.Offset = 0
+	}
+}
+
+func (l *ListStyle) setKey(key string) {
+	l.key = key
+}
+
+// keep takes over the scroll position from the list that l replaces.
+func (l *ListStyle) keep(old *ListStyle) {
+	l.list.Position = old.list.Position
+	l.Hpos = old.Hpos
+}
+
 // MakeList makes a vertical list
 func MakeList(th *Theme, a AnchorStrategy, widgets ...layout.Widget) layout.Widget {
+	return KeyedList(th, "", a, widgets...)
+}
+
+// KeyedList makes a vertical list that keeps its scroll position when the form is rebuilt. See Key.
+func KeyedList(th *Theme, key string, a AnchorStrategy, widgets ...layout.Widget) layout.Widget {
 	node := makeNode(widgets)
-	listStyle := ListStyle{
+	listStyle := &ListStyle{
 		list:           &layout.List{Axis: layout.Vertical},
+		key:            key,
 		VScrollBar:     MakeScrollbarStyle(th),
 		HScrollBar:     MakeScrollbarStyle(th),
 		AnchorStrategy: a,
 	}
+	if old, ok := th.form.keep(key, listStyle).(*ListStyle); ok {
+		listStyle.keep(old)
+	}
 
 	return func(gtx C) D {
 		var ch []layout.Widget
@@ -292,6 +330,148 @@ func MakeList(th *Theme, a AnchorStrategy, widgets ...layout.Widget) layout.Widg
 	}
 }
 
+// lazyList is a list where the row widgets are built on demand.
+type lazyList struct {
+	ListStyle
+	th    *Theme
+	count func() int
+	build func(i int) layout.Widget
+	rows  map[int]lazyRow
+	sel   *Selection
+	// rowKey returns the key of row i, and scope is the key prefix of the widgets in the rows.
+	rowKey func(i int) string
+	scope  string
+}
+
+// lazyRow is a built row, with the key it was built for.
+type lazyRow struct {
+	key string
+	w   layout.Widget
+}
+
+// RowKeyOption is the option type for the keys of the rows of a lazy list.
+type RowKeyOption struct {
+	key func(i int) string
+}
+
+// RowKeys is an option parameter that gives the rows of a lazy list a key, usually from the data shown
+// in row i. The widgets of a row keep their state when the row is built again with the same key, also
+// when the rows are moved by sorting or inserting. Without it, the key of a row is its index.
+func RowKeys(key func(i int) string) RowKeyOption {
+	return RowKeyOption{key: key}
+}
+
+func (o RowKeyOption) apply(cfg interface{}) {
+	if l, ok := cfg.(*lazyList); ok {
+		l.rowKey = o.key
+	}
+}
+
+// LazyList makes a vertical list with count() rows, where the widget for row i is made by build(i).
+// Rows are built only when they are scrolled into view, and are dropped again when they are
+// more than a page outside the visible window, so that a list can have a very large number of rows.
+// The widgets in a row keep their state, like hover, focus or an open dropdown, while the row is
+// built again, and they do not need keys for that. See RowKeys.
+// With the Selectable option, the rows can be selected with the mouse.
+func LazyList(th *Theme, a AnchorStrategy, count func() int, build func(i int) layout.Widget, options ...Option) layout.Widget {
+	l := newLazyList(th, a, count, build)
+	for _, option := range options {
+		option.apply(l)
+	}
+	if l.key != "" {
+		l.scope = l.key
+	}
+	if old, ok := th.form.keep(l.key, l).(*lazyList); ok {
+		l.keep(&old.ListStyle)
+		l.scope = old.scope
+	}
+	return l.Layout
+}
+
+func newLazyList(th *Theme, a AnchorStrategy, count func() int, build func(i int) layout.Widget) *lazyList {
+	l := &lazyList{
+		ListStyle: ListStyle{
+			list:           &layout.List{Axis: layout.Vertical},
+			VScrollBar:     MakeScrollbarStyle(th),
+			HScrollBar:     MakeScrollbarStyle(th),
+			AnchorStrategy: a,
+		},
+		th:    th,
+		count: count,
+		build: build,
+		rows:  make(map[int]lazyRow),
+	}
+	// A list without a key gets a scope of its own for the keys of its widgets.
+	l.scope = fmt.Sprintf("list%p", l)
+	return l
+}
+
+// Layout draws the visible rows and the scrollbars.
+func (l *lazyList) Layout(gtx C) D {
+	n := l.count()
+	dims := l.ListStyle.Layout(gtx, n, func(gtx C, i int) D {
+		return l.row(i)(gtx)
+	})
+	l.prune(n)
+	return dims
+}
+
+// lineKey returns the key of row i.
+func (l *lazyList) lineKey(i int) string {
+	if l.rowKey != nil {
+		return l.rowKey(i)
+	}
+	return strconv.Itoa(i)
+}
+
+// row returns the widget for row i, building it if it is not cached.
+func (l *lazyList) row(i int) layout.Widget {
+	key := l.lineKey(i)
+	if r, ok := l.rows[i]; ok && r.key == key {
+		return r.w
+	}
+	// The rows are built while the form is running, so they are not linked into its tab sequence.
+	// The widgets are keyed by the row, so that they take over the state of the row built before.
+	var w layout.Widget
+	l.th.form.scoped(l.scope+"/"+key, func() { w = l.build(i) })
+	if l.sel != nil {
+		r := &rowDef{
+			selected: func() bool { return l.sel.Selected(i) },
+			onClick: func(c Click) {
+				if c.NumClicks == 1 {
+					l.sel.Click(i, c.Modifiers, nil)
+				}
+			},
+		}
+		w = r.layout(l.th, func(gtx C) []int { return []int{gtx.Constraints.Max.X} }, w)
+	}
+	l.rows[i] = lazyRow{key: key, w: w}
+	return w
+}
+
+func (l *lazyList) setSelection(s *Selection) {
+	l.sel = s
+}
+
+// reset drops all built rows, so that they are built again when they are shown.
+// The rows take over the state of the widgets of the rows built before.
+func (l *lazyList) reset() {
+	l.rows = make(map[int]lazyRow)
+}
+
+// prune drops the rows that are more than one page outside the visible rows, and the state of their widgets.
+func (l *lazyList) prune(n int) {
+	pos := l.list.Position
+	first := pos.First - pos.Count
+	last := pos.First + 2*pos.Count
+	for i, r := range l.rows {
+		if i < first || i > last || i >= n {
+			delete(l.rows, i)
+			l.th.form.drop(l.scope + "/" + r.key)
+		}
+	}
+}
+
 // Layout the list and its scrollbar.
 func (l *ListStyle) Layout(gtx C, length int, w layout.ListElement) D {
 	originalConstraints := gtx.Constraints
@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"image"
+
+	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+)
+
+// MultiDropDownDef is a dropdown where several items can be selected.
+type MultiDropDownDef struct {
+	Widget
+	Clickable
+	selected *[]int
+	items    []string
+	// checked is the state of the checkbox of each item, and removers is the click on the close icon of each chip.
+	checked    []bool
+	removers   []gesture.Click
+	Visible    bool
+	list       layout.Widget
+	icon       *Icon
+	removeIcon *Icon
+	// cursor is moved by the arrow keys, which are not used by the dropdown.
+	cursor int
+	at     anchor
+}
+
+// MultiDropDown returns a dropdown where several items can be selected, bound to selected, which is the indices
+// of the selected items in increasing order. The list has a checkbox for each item, after buttons that select
+// all items or clear the selection. The closed box shows the selected items as chips, which are removed by
+// clicking their close icon, and "+3 more" for the items that do not fit in the width.
+func MultiDropDown(th *Theme, selected *[]int, items []string, options ...Option) *MultiDropDownDef {
+	b := &MultiDropDownDef{selected: selected, items: items}
+	b.SetupTabs(th.form)
+	b.th = th
+	b.index = &b.cursor
+	b.padding = th.LabelPadding
+	b.icon, _ = NewIcon(icons.NavigationArrowDropDown)
+	b.removeIcon, _ = NewIcon(icons.NavigationClose)
+	b.checked = make([]bool, len(items))
+	b.removers = make([]gesture.Click, len(items))
+	for _, option := range options {
+		option.apply(b)
+	}
+	// The widgets in the list are not in the tab sequence of the form.
+	th.form.detach(func() {
+		widgets := []layout.Widget{
+			Row(th, nil, nil,
+				TextButton(th, "Select all", Handler(func() { b.setAll(true) })),
+				TextButton(th, "Clear", Handler(func() { b.setAll(false) }))),
+		}
+		for i := range items {
+			i := i
+			widgets = append(widgets, Checkbox(th, items[i], &b.checked[i], func(v bool) { b.set(i, v) }))
+		}
+		b.list = MakeList(th, Overlay, widgets...)
+	})
+	if old, ok := th.form.keep(b.key, b).(*MultiDropDownDef); ok {
+		b.Clickable.keep(&old.Clickable)
+		b.Visible = old.Visible
+		b.at = old.at
+	}
+	return b
+}
+
+// sync checks the items in the selection, which may have been changed by the program.
+func (b *MultiDropDownDef) sync() {
+	for i := range b.checked {
+		b.checked[i] = false
+	}
+	for _, i := range *b.selected {
+		if i >= 0 && i < len(b.checked) {
+			b.checked[i] = true
+		}
+	}
+}
+
+// set selects or unselects item i.
+func (b *MultiDropDownDef) set(i int, v bool) {
+	b.checked[i] = v
+	b.update()
+}
+
+// setAll selects all items, or clears the selection.
+func (b *MultiDropDownDef) setAll(v bool) {
+	for i := range b.checked {
+		b.checked[i] = v
+	}
+	b.update()
+}
+
+// update makes the selection from the checked items.
+func (b *MultiDropDownDef) update() {
+	var sel []int
+	for i, c := range b.checked {
+		if c {
+			sel = append(sel, i)
+		}
+	}
+	*b.selected = sel
+}
+
+// Layout draws the dropdown, and the list when it is open.
+func (b *MultiDropDownDef) Layout(gtx C) D {
+	return b.padding.Layout(gtx, b.layout)
+}
+
+func (b *MultiDropDownDef) layout(gtx C) D {
+	if b.width.V > 0 {
+		gtx.Constraints.Min.X = gtx.Px(b.width)
+		gtx.Constraints.Max.X = gtx.Px(b.width)
+	}
+	b.sync()
+	for _, e := range gtx.Events(&b.Visible) {
+		if e, ok := e.(pointer.Event); ok && e.Type == pointer.Press {
+			b.Visible = false
+		}
+	}
+	gtx.Constraints.Min.X = gtx.Constraints.Max.X
+	dims := layout.Stack{Alignment: layout.W}.Layout(gtx,
+		layout.Expanded(b.layoutBackground),
+		layout.Stacked(func(gtx C) D {
+			return b.th.DropDownPadding.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, b.layoutChips),
+					layout.Rigid(func(gtx C) D {
+						size := gtx.Px(b.th.TextSize.Scale(1.5))
+						gtx.Constraints = layout.Exact(image.Pt(size, size))
+						return b.icon.Layout(gtx, b.th.OnBackground)
+					}),
+				)
+			})
+		}),
+	)
+	b.at.layout(gtx, b.th.form, dims.Size)
+	for b.Clicked() {
+		b.Visible = !b.Visible
+	}
+	if b.Visible {
+		// A checkbox row is as high as the checkbox icon, which is twice the text size.
+		layoutPopup(gtx, b.th, dims, b.list, &b.Visible, &b.at, popupRows*gtx.Px(b.th.TextSize.Scale(2)))
+	}
+	pointer.CursorNameOp{Name: pointer.CursorPointer}.Add(gtx.Ops)
+	return dims
+}
+
+// layoutBackground draws the background and the border, and handles the clicks and keys that open the list.
+func (b *MultiDropDownDef) layoutBackground(gtx C) D {
+	if b.Focused() || b.Hovered() {
+		Shadow(b.th.CornerRadius, b.th.Elevation).Layout(gtx)
+	}
+	rr := Pxr(gtx, b.th.CornerRadius)
+	if rr > float32(gtx.Constraints.Min.Y)/2.0 {
+		rr = float32(gtx.Constraints.Min.Y) / 2.0
+	}
+	outline := f32.Rectangle{Max: layout.FPt(gtx.Constraints.Min)}
+	paint.FillShape(gtx.Ops, b.th.Background, clip.UniformRRect(outline, rr).Op(gtx.Ops))
+	LayoutBorder(&b.Clickable, b.th)(gtx)
+	b.LayoutClickable(gtx)
+	b.HandleClicks(gtx)
+	b.HandleKeys(gtx)
+	return D{Size: gtx.Constraints.Min}
+}
+
+// layoutChips draws the selected items as chips, as many as fit in the width, followed by the number
+// of items not shown.
+func (b *MultiDropDownDef) layoutChips(gtx C) D {
+	sel := *b.selected
+	gap := gtx.Px(b.th.TextSize.Scale(0.25))
+	height := gtx.Px(b.th.TextSize.Scale(1.5))
+	max := gtx.Constraints.Max.X
+	gtx.Constraints.Min = image.Point{}
+	x, shown := 0, 0
+	for k, i := range sel {
+		if i < 0 || i >= len(b.items) {
+			continue
+		}
+		macro := op.Record(gtx.Ops)
+		dims := b.chip(gtx, i)
+		call := macro.Stop()
+		need := dims.Size.X
+		if rest := len(sel) - k - 1; rest > 0 {
+			need += gap + textWidth(gtx, b.th, fmt.Sprintf("+%d more", rest))
+		}
+		// The first chip is always shown, and is cut at the right side if it is too wide.
+		if k > 0 && x+need > max {
+			break
+		}
+		st := op.Offset(f32.Pt(float32(x), float32(height-dims.Size.Y)/2)).Push(gtx.Ops)
+		call.Add(gtx.Ops)
+		st.Pop()
+		x += dims.Size.X + gap
+		shown = k + 1
+	}
+	if shown < len(sel) {
+		macro := op.Record(gtx.Ops)
+		paint.ColorOp{Color: b.th.OnBackground}.Add(gtx.Ops)
+		dims := aLabel{MaxLines: 1}.Layout(gtx, b.th.Shaper, text.Font{}, b.th.TextSize, fmt.Sprintf("+%d more", len(sel)-shown))
+		call := macro.Stop()
+		st := op.Offset(f32.Pt(float32(x), float32(height-dims.Size.Y)/2)).Push(gtx.Ops)
+		call.Add(gtx.Ops)
+		st.Pop()
+	}
+	return D{Size: image.Pt(max, height)}
+}
+
+// chip draws item i as a rounded label with a close icon that removes it from the selection.
+func (b *MultiDropDownDef) chip(gtx C, i int) D {
+	for _, e := range b.removers[i].Events(gtx) {
+		if e.Type == gesture.TypeClick {
+			b.set(i, false)
+		}
+	}
+	size := gtx.Px(b.th.TextSize)
+	pad := b.th.TextSize.Scale(0.5)
+	macro := op.Record(gtx.Ops)
+	dims := layout.Inset{Left: pad, Right: pad.Scale(0.5)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				paint.ColorOp{Color: b.th.OnBackground}.Add(gtx.Ops)
+				return aLabel{MaxLines: 1}.Layout(gtx, b.th.Shaper, text.Font{}, b.th.TextSize.Scale(0.9), b.items[i])
+			}),
+			layout.Rigid(func(gtx C) D {
+				gtx.Constraints = layout.Exact(image.Pt(size, size))
+				dims := b.removeIcon.Layout(gtx, b.th.OnBackground)
+				defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+				b.removers[i].Add(gtx.Ops)
+				return dims
+			}),
+		)
+	})
+	call := macro.Stop()
+	r := float32(dims.Size.Y) / 2
+	paint.FillShape(gtx.Ops, MulAlpha(b.th.Primary, 50), clip.UniformRRect(f32.Rectangle{Max: layout.FPt(dims.Size)}, r).Op(gtx.Ops))
+	call.Add(gtx.Ops)
+	return dims
+}
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+)
+
+// pageSizes is the page sizes offered by a paginator, and pageSlots is the number of page buttons
+// and ellipses shown between the arrow buttons.
+var pageSizes = []int{10, 25, 50, 100}
+
+const pageSlots = 7
+
+// PaginatorDef is a row of buttons that moves between the pages of a list of rows.
+type PaginatorDef struct {
+	Widget
+	page     *int
+	pageSize *int
+	total    func() int
+	// size and index is the page size and the dropdown index at the last layout, used to find if the size
+	// was changed by the program or in the dropdown.
+	size      int
+	index     int
+	sizes     []int
+	sizeIndex int
+	// slots is the page buttons, and slotPage is the page of each, -1 for an ellipsis or -2 if it is not used.
+	slots    []*ButtonDef
+	slotPage []int
+	atFirst  bool
+	atLast   bool
+	widgets  []layout.Widget
+}
+
+// Paginator returns a row with buttons for the first, previous, next and last page, buttons for the
+// page numbers around the current page, a dropdown for the page size and the range of rows shown.
+// page is the current page, counted from 0, and pageSize the number of rows on a page. They are
+// changed by the paginator, and can be set by the program. total is the number of rows.
+// Tables use a paginator made by TableDef.Paginator, see Pages.
+func Paginator(th *Theme, page *int, pageSize *int, total int, options ...Option) layout.Widget {
+	return newPaginator(th, page, pageSize, func() int { return total }, options...).Layout
+}
+
+func newPaginator(th *Theme, page *int, pageSize *int, total func() int, options ...Option) *PaginatorDef {
+	p := &PaginatorDef{page: page, pageSize: pageSize, total: total}
+	p.th = th
+	p.Apply(options...)
+	if *pageSize <= 0 {
+		*pageSize = pageSizes[1]
+	}
+	p.size = *pageSize
+	p.sizes = append([]int(nil), pageSizes...)
+	// A page size that is not one of the usual ones is added to them.
+	p.sizeIndex = sort.SearchInts(p.sizes, *pageSize)
+	if p.sizeIndex == len(p.sizes) || p.sizes[p.sizeIndex] != *pageSize {
+		p.sizes = append(p.sizes[:p.sizeIndex], append([]int{*pageSize}, p.sizes[p.sizeIndex:]...)...)
+	}
+	p.index = p.sizeIndex
+	// The dropdown gets a key when the paginator has one, so that it stays open when the form is rebuilt.
+	var keyed []Option
+	if p.key != "" {
+		keyed = append(keyed, Key(p.key+".size"))
+	}
+	var items []string
+	for _, n := range p.sizes {
+		items = append(items, strconv.Itoa(n))
+	}
+	firstIcon, _ := NewIcon(icons.NavigationFirstPage)
+	prevIcon, _ := NewIcon(icons.NavigationChevronLeft)
+	nextIcon, _ := NewIcon(icons.NavigationChevronRight)
+	lastIcon, _ := NewIcon(icons.NavigationLastPage)
+	p.widgets = append(p.widgets,
+		RoundButton(th, firstIcon, Hint("First page"), Disable(&p.atFirst), Handler(func() { p.setPage(0) })),
+		RoundButton(th, prevIcon, Hint("Previous page"), Disable(&p.atFirst), Handler(func() { p.setPage(*p.page - 1) })))
+	for i := 0; i < pageSlots; i++ {
+		i := i
+		b := newButton(Text, th, "", Handler(func() { p.setPage(p.slotPage[i]) }))
+		p.slots = append(p.slots, b)
+		p.widgets = append(p.widgets, p.slot(i))
+	}
+	p.slotPage = make([]int, pageSlots)
+	p.widgets = append(p.widgets,
+		RoundButton(th, nextIcon, Hint("Next page"), Disable(&p.atLast), Handler(func() { p.setPage(*p.page + 1) })),
+		RoundButton(th, lastIcon, Hint("Last page"), Disable(&p.atLast), Handler(func() { p.setPage(p.pages() - 1) })),
+		Label(th, "Rows per page"),
+		DropDown(th, &p.sizeIndex, items, append(keyed, W(80))...).Layout,
+		Value(th, p.rangeText))
+	return p
+}
+
+// pages returns the number of pages, which is at least one.
+func (p *PaginatorDef) pages() int {
+	n := (p.total() + *p.pageSize - 1) / *p.pageSize
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// setPage goes to page n, limited to the pages there are.
+func (p *PaginatorDef) setPage(n int) {
+	*p.page = clampInt(n, 0, p.pages()-1)
+}
+
+// rangeText returns the rows shown, like "101–200 of 10000".
+func (p *PaginatorDef) rangeText() string {
+	total := p.total()
+	if total == 0 {
+		return "0 of 0"
+	}
+	first := *p.page * *p.pageSize
+	last := first + *p.pageSize
+	if last > total {
+		last = total
+	}
+	return fmt.Sprintf("%d–%d of %d", first+1, last, total)
+}
+
+// arrange finds the page of each slot. The first and last page are always shown, with the pages around
+// the current page between them, and an ellipsis where pages are left out.
+func (p *PaginatorDef) arrange() {
+	n, cur := p.pages(), *p.page
+	for i := range p.slotPage {
+		p.slotPage[i] = -2
+	}
+	switch {
+	case n <= pageSlots:
+		for i := 0; i < n; i++ {
+			p.slotPage[i] = i
+		}
+	case cur < pageSlots-3:
+		for i := 0; i < pageSlots-2; i++ {
+			p.slotPage[i] = i
+		}
+		p.slotPage[pageSlots-2], p.slotPage[pageSlots-1] = -1, n-1
+	case cur > n-pageSlots+2:
+		p.slotPage[0], p.slotPage[1] = 0, -1
+		for i := 2; i < pageSlots; i++ {
+			p.slotPage[i] = n - pageSlots + i
+		}
+	default:
+		p.slotPage[0], p.slotPage[1] = 0, -1
+		for i := 2; i < pageSlots-2; i++ {
+			p.slotPage[i] = cur + i - pageSlots/2
+		}
+		p.slotPage[pageSlots-2], p.slotPage[pageSlots-1] = -1, n-1
+	}
+}
+
+// slot returns the widget for page slot i, which is a page button, an ellipsis or nothing.
+func (p *PaginatorDef) slot(i int) layout.Widget {
+	ellipsis := Label(p.th, "…")
+	return func(gtx C) D {
+		switch page := p.slotPage[i]; {
+		case page == -1:
+			return ellipsis(gtx)
+		case page < 0:
+			return D{}
+		default:
+			b := p.slots[i]
+			b.Text = strconv.Itoa(page + 1)
+			b.Style = Text
+			if page == *p.page {
+				b.Style = Contained
+			}
+			return b.Layout(gtx)
+		}
+	}
+}
+
+// sync takes over a page size chosen in the dropdown or set by the program. When the size is chosen
+// in the dropdown, the page with the first row of the current page is shown.
+func (p *PaginatorDef) sync() {
+	if *p.pageSize <= 0 {
+		*p.pageSize = p.size
+	}
+	if p.sizeIndex != p.index {
+		first := *p.page * *p.pageSize
+		*p.pageSize = p.sizes[clampInt(p.sizeIndex, 0, len(p.sizes)-1)]
+		*p.page = first / *p.pageSize
+	} else if *p.pageSize != p.size {
+		if i := sort.SearchInts(p.sizes, *p.pageSize); i < len(p.sizes) && p.sizes[i] == *p.pageSize {
+			p.sizeIndex = i
+		}
+	}
+	p.index, p.size = p.sizeIndex, *p.pageSize
+	*p.page = clampInt(*p.page, 0, p.pages()-1)
+	p.atFirst = *p.page == 0
+	p.atLast = *p.page == p.pages()-1
+}
+
+// Layout draws the paginator. When the page is changed, the window is drawn again, so that widgets drawn
+// before the paginator show the new page.
+func (p *PaginatorDef) Layout(gtx C) D {
+	p.sync()
+	p.arrange()
+	page, size := *p.page, *p.pageSize
+	var children []layout.FlexChild
+	for _, w := range p.widgets {
+		children = append(children, layout.Rigid(w))
+	}
+	dims := layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+	p.sync()
+	if *p.page != page || *p.pageSize != size {
+		op.InvalidateOp{}.Add(gtx.Ops)
+	}
+	return dims
+}
+
+// Pages is an option parameter that shows one page of rows at a time, instead of scrolling through all rows.
+// page is the page shown, counted from 0, and pageSize the number of rows on a page. The page is changed
+// with a paginator made by TableDef.Paginator, or by the program, and when the cursor is moved past the
+// rows shown. Group headers and footers are counted as rows.
+func Pages(page *int, pageSize *int) TableOption {
+	return func(t *TableDef) {
+		t.page = page
+		t.pageSize = pageSize
+		if *pageSize <= 0 {
+			*pageSize = pageSizes[1]
+		}
+	}
+}
+
+// Paginator returns a paginator for a table with the Pages option, with the number of rows found by the filters.
+func (t *TableDef) Paginator(options ...Option) layout.Widget {
+	if t.page == nil {
+		return empty
+	}
+	return newPaginator(t.th, t.page, t.pageSize, t.lineCount, options...).Layout
+}
+
+// pageStart returns the first line in the list that is shown on the current page.
+func (t *TableDef) pageStart() int {
+	if t.page == nil {
+		return 0
+	}
+	return *t.page * *t.pageSize
+}
+
+// showPage starts the list at the top of the page, when the page is changed. The rows are made again,
+// since the lines of the list are now other rows.
+func (t *TableDef) showPage() {
+	if t.page == nil {
+		return
+	}
+	if n := (t.lineCount() + *t.pageSize - 1) / *t.pageSize; *t.page >= n {
+		*t.page = clampInt(n-1, 0, *t.page)
+	}
+	if start := t.pageStart(); start != t.shownStart {
+		t.shownStart = start
+		t.list.list.Position = layout.Position{}
+		t.list.reset()
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaginatorArrange(t *testing.T) {
+	tests := []struct {
+		total, page int
+		want        []int
+	}{
+		{0, 0, []int{0, -2, -2, -2, -2, -2, -2}},
+		{30, 2, []int{0, 1, 2, -2, -2, -2, -2}},
+		{70, 6, []int{0, 1, 2, 3, 4, 5, 6}},
+		{200, 0, []int{0, 1, 2, 3, 4, -1, 19}},
+		{200, 3, []int{0, 1, 2, 3, 4, -1, 19}},
+		{200, 4, []int{0, -1, 3, 4, 5, -1, 19}},
+		{200, 15, []int{0, -1, 14, 15, 16, -1, 19}},
+		{200, 16, []int{0, -1, 15, 16, 17, 18, 19}},
+		{200, 19, []int{0, -1, 15, 16, 17, 18, 19}},
+	}
+	for _, test := range tests {
+		page, size, total := test.page, 10, test.total
+		p := &PaginatorDef{page: &page, pageSize: &size, total: func() int { return total }, slotPage: make([]int, pageSlots)}
+		p.arrange()
+		if !reflect.DeepEqual(p.slotPage, test.want) {
+			t.Errorf("page %d of %d rows has slots %v, want %v", test.page, test.total, p.slotPage, test.want)
+		}
+	}
+}
+
+func TestPaginatorRangeText(t *testing.T) {
+	tests := []struct {
+		total, page int
+		want        string
+	}{
+		{0, 0, "0 of 0"},
+		{60, 0, "1–25 of 60"},
+		{60, 1, "26–50 of 60"},
+		{60, 2, "51–60 of 60"},
+	}
+	for _, test := range tests {
+		page, size, total := test.page, 25, test.total
+		p := &PaginatorDef{page: &page, pageSize: &size, total: func() int { return total }}
+		if got := p.rangeText(); got != test.want {
+			t.Errorf("page %d of %d rows is %q, want %q", test.page, test.total, got, test.want)
+		}
+	}
+}
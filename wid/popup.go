@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"image"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+// popupRows is the number of rows shown in the list of a dropdown before it scrolls, unless set with MaxRows.
+const popupRows = 12
+
+// anchor finds the position of a widget in the window, so that its popup can be placed within the window.
+// Gio does not tell a widget where it is, but a press on the widget is seen both by the form, which tracks
+// the pointer in window coordinates, and by the widget, in its own coordinates, and the difference is the
+// position of the widget. The position is found again at each press, which is what opens the popup.
+type anchor struct {
+	origin image.Point
+	known  bool
+}
+
+// layout finds the position of the widget from the presses since the last frame, and adds the
+// input that gets the presses on the widget, with the given size, in the next frame.
+func (a *anchor) layout(gtx C, f *Form, size image.Point) {
+	var pos f32.Point
+	seen := false
+	for _, e := range gtx.Events(a) {
+		if e, ok := e.(pointer.Event); ok && e.Type == pointer.Press {
+			pos, seen = e.Position, true
+		}
+	}
+	if seen && f != nil && f.size != (image.Point{}) {
+		d := f.pointer.Sub(pos)
+		a.origin = image.Pt(int(math.Round(float64(d.X))), int(math.Round(float64(d.Y))))
+		a.known = true
+	}
+	defer pointer.PassOp{}.Push(gtx.Ops).Pop()
+	defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+	pointer.InputOp{Tag: a, Types: pointer.Press}.Add(gtx.Ops)
+}
+
+// trackPointer sends all pointer moves and presses in the window to tag, without taking them from other widgets.
+// The input is deferred, so that it is above all other widgets.
+func trackPointer(gtx C, tag event.Tag) {
+	macro := op.Record(gtx.Ops)
+	st := pointer.PassOp{}.Push(gtx.Ops)
+	area := clip.Rect(image.Rect(-inf, -inf, inf, inf)).Push(gtx.Ops)
+	pointer.InputOp{Tag: tag, Types: pointer.Move | pointer.Press}.Add(gtx.Ops)
+	area.Pop()
+	st.Pop()
+	op.Defer(gtx.Ops, macro.Stop())
+}
+
+// layoutPopup draws the list of options at a box with the size given by dims, above the other widgets.
+// The list opens below the box, or above it when it does not fit below and there is more room above.
+// It is at most maxHeight high, if maxHeight is above 0, and is moved left to stay within the window.
+// The room around the box is found from the position given by at and the window size of the form, and if
+// they are not known, the list opens below the box, within the constraints.
+// If closeTag is not nil, clicks outside the list are sent to it as pointer presses, so that the list can be closed.
+func layoutPopup(gtx C, th *Theme, dims D, list layout.Widget, closeTag event.Tag, at *anchor, maxHeight int) {
+	below, above := gtx.Constraints.Max.Y-dims.Size.Y-5, 0
+	f := th.form
+	placed := at != nil && at.known && f != nil && f.size != (image.Point{})
+	if placed {
+		below = f.size.Y - at.origin.Y - dims.Size.Y
+		above = at.origin.Y
+	}
+	gtx.Constraints.Max.Y = max(below, above)
+	if maxHeight > 0 {
+		gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, maxHeight)
+	}
+	gtx.Constraints.Max.Y = max(gtx.Constraints.Max.Y, 0)
+	gtx.Constraints.Min = image.Pt(dims.Size.X, min(dims.Size.Y, gtx.Constraints.Max.Y))
+	macro := op.Record(gtx.Ops)
+	d := list(gtx)
+	listClipRect := f32.Rect(0, 0, float32(gtx.Constraints.Min.X), float32(d.Size.Y))
+	call := macro.Stop()
+	pos := image.Pt(0, dims.Size.Y)
+	if d.Size.Y > below && above > below {
+		pos.Y = -d.Size.Y
+	}
+	if placed && at.origin.X+gtx.Constraints.Min.X > f.size.X {
+		pos.X = max(f.size.X-gtx.Constraints.Min.X-at.origin.X, -at.origin.X)
+	}
+	macro = op.Record(gtx.Ops)
+	if closeTag != nil {
+		area := clip.Rect(image.Rect(-inf, -inf, inf, inf)).Push(gtx.Ops)
+		pointer.InputOp{Tag: closeTag, Types: pointer.Press}.Add(gtx.Ops)
+		area.Pop()
+	}
+	op.Offset(layout.FPt(pos)).Add(gtx.Ops)
+	stack := clip.UniformRRect(listClipRect, 0).Push(gtx.Ops)
+	paint.Fill(gtx.Ops, th.Background)
+	// Draw a border around all options
+	paintBorder(gtx, listClipRect, th.OnBackground, th.BorderThickness, unit.Value{})
+	if closeTag != nil {
+		// Clicks between the options must not close the list.
+		pointer.InputOp{Tag: &listClipRect, Types: pointer.Press}.Add(gtx.Ops)
+	}
+	call.Add(gtx.Ops)
+	stack.Pop()
+	call = macro.Stop()
+	op.Defer(gtx.Ops, call)
+}
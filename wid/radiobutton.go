@@ -38,7 +38,10 @@ func RadioButton(th *Theme, output *string, key string, label string, options ..
 	for _, option := range options {
 		option.apply(&r)
 	}
-	r.SetupTabs()
+	r.SetupTabs(th.form)
+	if old, ok := th.form.keep(r.key, &r).(*RadioButtonStyle); ok {
+		r.Clickable.keep(&old.Clickable)
+	}
 	return func(gtx C) D {
 		isSelected := *r.Output == r.Key
 		dims := r.layout(gtx, isSelected)
@@ -19,24 +19,37 @@ type Resize struct {
 	Length float32
 	drag   gesture.Drag
 	start  int
+	key    string
 }
 
 // SplitHorizontal is used to layout two widgets with a vertical splitter between.
-func SplitHorizontal(th *Theme, ratio float32, w1 layout.Widget, w2 layout.Widget) func(gtx C) D {
-	rs := Resize{Theme: th, ratio: ratio, axis: layout.Horizontal}
-	return func(gtx C) D {
-		return rs.Layout(gtx, w1, w2)
-	}
+func SplitHorizontal(th *Theme, ratio float32, w1 layout.Widget, w2 layout.Widget, options ...Option) func(gtx C) D {
+	return split(th, layout.Horizontal, ratio, w1, w2, options...)
 }
 
 // SplitVertical is used to layout two widgets with a vertical splitter between.
-func SplitVertical(th *Theme, ratio float32, w1 layout.Widget, w2 layout.Widget) func(gtx C) D {
-	rs := Resize{Theme: th, ratio: ratio, axis: layout.Vertical}
+func SplitVertical(th *Theme, ratio float32, w1 layout.Widget, w2 layout.Widget, options ...Option) func(gtx C) D {
+	return split(th, layout.Vertical, ratio, w1, w2, options...)
+}
+
+func split(th *Theme, axis layout.Axis, ratio float32, w1 layout.Widget, w2 layout.Widget, options ...Option) func(gtx C) D {
+	rs := &Resize{Theme: th, ratio: ratio, axis: axis}
+	for _, option := range options {
+		option.apply(rs)
+	}
+	// A rebuilt splitter keeps the ratio the user has dragged it to.
+	if old, ok := th.form.keep(rs.key, rs).(*Resize); ok {
+		rs.ratio = old.ratio
+	}
 	return func(gtx C) D {
 		return rs.Layout(gtx, w1, w2)
 	}
 }
 
+func (rs *Resize) setKey(key string) {
+	rs.key = key
+}
+
 // Layout displays w1 and w2 with handle in between.
 func (rs *Resize) Layout(gtx C, w1 layout.Widget, w2 layout.Widget) D {
 	// Compute the first widget's max width/height.
@@ -15,25 +15,56 @@ import (
 
 type rowDef struct {
 	Clickable
+	// selected returns true when the row is drawn as selected, and onClick is called for each click on the row.
+	selected func() bool
+	onClick  func(c Click)
+	// frozen is the number of leading widgets that stay in place when the row is scrolled horizontally,
+	// and hpos returns the horizontal scroll position. The other widgets scroll under the frozen ones.
+	frozen int
+	hpos   func() int
 }
 
 // Row returns a widget grid row with selectable color.
 func Row(th *Theme, selected *bool, weights []float32, widgets ...layout.Widget) layout.Widget {
-	r := rowDef{}
 	if weights == nil {
-		weights = []float32{1, 1, 1, 1, 1, 1, 1, 1}
+		weights = make([]float32, len(widgets))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	widths := make([]int, len(widgets))
+	return fixedRow(th, selected, func(gtx C) []int {
+		calcWidths(gtx, th.TextSize, weights[:len(widgets)], widths)
+		return widths
+	}, widgets...)
+}
+
+// fixedRow returns a row where the width of each widget in pixels is returned by widthsOf,
+// so that rows can share widths that are calculated elsewhere, like the columns of a table.
+func fixedRow(th *Theme, selected *bool, widthsOf func(gtx C) []int, widgets ...layout.Widget) layout.Widget {
+	r := &rowDef{
+		selected: func() bool { return selected != nil && *selected },
+		onClick: func(c Click) {
+			if selected != nil {
+				*selected = !*selected
+			}
+		},
 	}
+	return r.layout(th, widthsOf, widgets...)
+}
+
+// layout returns the widget for the row r.
+func (r *rowDef) layout(th *Theme, widthsOf func(gtx C) []int, widgets ...layout.Widget) layout.Widget {
 	dims := make([]D, len(widgets))
 	call := make([]op.CallOp, len(widgets))
-	widths := make([]int, len(widgets))
 	return func(gtx C) D {
 		bgColor := th.Background
 		if r.Hovered() {
 			bgColor = Interpolate(th.Background, th.Primary, 0.05)
-		} else if selected != nil && *selected {
+		} else if r.selected() {
 			bgColor = Interpolate(th.Background, th.Primary, 0.1)
 		}
-		calcWidths(gtx, th.TextSize, weights[:len(widgets)], widths)
+		widths := widthsOf(gtx)
 		// Check child sizes and make macros for each widget in a row
 		yMax := 0
 		c := gtx
@@ -54,14 +85,44 @@ func Row(th *Theme, selected *bool, weights []float32, widgets ...layout.Widget)
 		}
 		macro := op.Record(gtx.Ops)
 		pos := float32(0)
+		frozen, width := float32(0), 0
+		for i := range widgets {
+			if i < r.frozen {
+				frozen += float32(dims[i].Size.X)
+			}
+			width += dims[i].Size.X
+		}
+		hpos := float32(0)
+		if r.hpos != nil {
+			hpos = float32(r.hpos())
+		}
+		// The scrolling children are clipped to the right of the frozen ones, also for pointer events.
+		var cl clip.Stack
+		if r.frozen > 0 {
+			cl = clip.Rect{Min: image.Pt(int(hpos+frozen), 0), Max: image.Pt(width, yMax)}.Push(gtx.Ops)
+		}
 		// Generate all the rendering commands for the children,
 		// translated to correct location.
 		for i := range widgets {
-			trans := op.Offset(f32.Pt(pos, 0)).Push(gtx.Ops)
-			call[i].Add(gtx.Ops)
-			trans.Pop()
+			if i >= r.frozen {
+				trans := op.Offset(f32.Pt(pos, 0)).Push(gtx.Ops)
+				call[i].Add(gtx.Ops)
+				trans.Pop()
+			}
 			pos += float32(dims[i].Size.X)
 		}
+		if r.frozen > 0 {
+			cl.Pop()
+			// Draw the frozen children where the scroll position is, on their own background.
+			paint.FillShape(gtx.Ops, bgColor, clip.Rect{Min: image.Pt(int(hpos), 0), Max: image.Pt(int(hpos+frozen), yMax)}.Op())
+			x := hpos
+			for i := 0; i < r.frozen && i < len(widgets); i++ {
+				trans := op.Offset(f32.Pt(x, 0)).Push(gtx.Ops)
+				call[i].Add(gtx.Ops)
+				trans.Pop()
+				x += float32(dims[i].Size.X)
+			}
+		}
 		// The row width is now the position after the last drawn widget.
 		dim := D{Size: image.Pt(int(pos), yMax)}
 		drawAll := macro.Stop()
@@ -72,7 +133,9 @@ func Row(th *Theme, selected *bool, weights []float32, widgets ...layout.Widget)
 		gtx.Constraints.Min = dim.Size
 		r.LayoutClickable(gtx)
 		r.HandleClicks(gtx)
-		r.HandleToggle(selected, nil)
+		for _, c := range r.Clicks() {
+			r.onClick(c)
+		}
 		// Then play the macro to draw all the children.
 		drawAll.Add(gtx.Ops)
 		return dim
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"sort"
+
+	"gioui.org/io/key"
+)
+
+// Selection is the selected rows of a table or a list, given by their index in the data.
+// A click selects one row, Ctrl-click toggles a row and Shift-click selects the rows from the anchor,
+// which is the row clicked last without Shift.
+// The zero value is an empty selection, ready to use.
+type Selection struct {
+	rows   map[int]bool
+	anchor int
+	// OnChange is called after the user has changed the selection.
+	OnChange func()
+	// changed is called for each row that is selected or deselected, to keep a bool field in sync.
+	changed func(i int, selected bool)
+}
+
+// SelectionOption is the option type for sharing a selection.
+type SelectionOption struct {
+	s *Selection
+}
+
+// Selectable is an option parameter that lets the user select rows in a table or a lazy list,
+// with the selection kept in s. Several widgets can use the same selection.
+func Selectable(s *Selection) SelectionOption {
+	return SelectionOption{s: s}
+}
+
+func (o SelectionOption) apply(cfg interface{}) {
+	if w, ok := cfg.(interface{ setSelection(*Selection) }); ok {
+		w.setSelection(o.s)
+	}
+}
+
+// Selected returns true if row i is selected.
+func (s *Selection) Selected(i int) bool {
+	return s.rows[i]
+}
+
+// Len returns the number of selected rows.
+func (s *Selection) Len() int {
+	return len(s.rows)
+}
+
+// Indices returns the selected rows in increasing order.
+func (s *Selection) Indices() []int {
+	var rows []int
+	for i := range s.rows {
+		rows = append(rows, i)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Anchor returns the row that a range selection starts from.
+func (s *Selection) Anchor() int {
+	return s.anchor
+}
+
+// Set selects or deselects row i. OnChange is not called for changes made by the program.
+func (s *Selection) Set(i int, selected bool) {
+	if s.rows[i] == selected {
+		return
+	}
+	if s.rows == nil {
+		s.rows = make(map[int]bool)
+	}
+	if selected {
+		s.rows[i] = true
+	} else {
+		delete(s.rows, i)
+	}
+	if s.changed != nil {
+		s.changed(i, selected)
+	}
+}
+
+// Clear deselects all rows.
+func (s *Selection) Clear() {
+	for i := range s.rows {
+		s.Set(i, false)
+	}
+}
+
+// Click changes the selection as a click on row i with the modifiers mods does. Order is the rows in the
+// order they are shown, used to find the rows between the anchor and i. If it is nil, the rows are
+// shown in the order of their index.
+func (s *Selection) Click(i int, mods key.Modifiers, order []int) {
+	switch {
+	case mods.Contain(key.ModShift):
+		s.Extend(i, order)
+		return
+	case mods.Contain(key.ModShortcut):
+		s.Set(i, !s.Selected(i))
+	default:
+		s.Clear()
+		s.Set(i, true)
+	}
+	s.anchor = i
+	s.notify()
+}
+
+// Extend selects the rows from the anchor to row i, and deselects all other rows.
+// It is used for Shift-click and Shift with the arrow keys.
+func (s *Selection) Extend(i int, order []int) {
+	s.Clear()
+	for _, r := range rowRange(s.anchor, i, order) {
+		s.Set(r, true)
+	}
+	s.notify()
+}
+
+// read sets the selection of the n rows from get, without calling changed or OnChange.
+func (s *Selection) read(n int, get func(i int) bool) {
+	s.rows = make(map[int]bool)
+	for i := 0; i < n; i++ {
+		if get(i) {
+			s.rows[i] = true
+		}
+	}
+}
+
+// update sets row i as selected by the user, like with a checkbox.
+func (s *Selection) update(i int, selected bool) {
+	s.Set(i, selected)
+	s.anchor = i
+	s.notify()
+}
+
+func (s *Selection) notify() {
+	if s.OnChange != nil {
+		s.OnChange()
+	}
+}
+
+// rowRange returns the rows shown from a to b, both included. If a is not shown, only b is returned.
+func rowRange(a, b int, order []int) []int {
+	if order == nil {
+		if a > b {
+			a, b = b, a
+		}
+		var rows []int
+		for i := a; i <= b; i++ {
+			rows = append(rows, i)
+		}
+		return rows
+	}
+	pa, pb := -1, -1
+	for p, r := range order {
+		if r == a {
+			pa = p
+		}
+		if r == b {
+			pb = p
+		}
+	}
+	if pb < 0 {
+		return nil
+	}
+	if pa < 0 {
+		return []int{b}
+	}
+	if pa > pb {
+		pa, pb = pb, pa
+	}
+	return append([]int(nil), order[pa:pb+1]...)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestRowRange(t *testing.T) {
+	order := []int{4, 2, 7, 1}
+	tests := []struct {
+		a, b  int
+		order []int
+		want  []int
+	}{
+		{1, 3, nil, []int{1, 2, 3}},
+		{3, 1, nil, []int{1, 2, 3}},
+		{2, 2, nil, []int{2}},
+		{4, 7, order, []int{4, 2, 7}},
+		{1, 2, order, []int{2, 7, 1}},
+		{9, 7, order, []int{7}},
+		{4, 9, order, nil},
+	}
+	for _, test := range tests {
+		if got := rowRange(test.a, test.b, test.order); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("rowRange(%d, %d, %v) = %v, want %v", test.a, test.b, test.order, got, test.want)
+		}
+	}
+}
+
+func TestSelectionClick(t *testing.T) {
+	var s Selection
+	changes, notified := 0, 0
+	s.changed = func(i int, selected bool) { changes++ }
+	s.OnChange = func() { notified++ }
+	order := []int{3, 0, 2, 1}
+	s.Click(0, 0, order)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{0}) || s.Anchor() != 0 {
+		t.Fatalf("click selected %v with anchor %d", got, s.Anchor())
+	}
+	s.Click(1, key.ModShift, order)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{0, 1, 2}) || s.Anchor() != 0 {
+		t.Errorf("shift-click selected %v with anchor %d", got, s.Anchor())
+	}
+	s.Click(3, key.ModShortcut, order)
+	s.Click(2, key.ModShortcut, order)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{0, 1, 3}) || s.Anchor() != 2 {
+		t.Errorf("ctrl-click selected %v with anchor %d", got, s.Anchor())
+	}
+	s.Click(2, 0, order)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("click selected %v", got)
+	}
+	if notified != 5 {
+		t.Errorf("OnChange was called %d times, want 5", notified)
+	}
+	if changes != 11 {
+		t.Errorf("changed was called %d times, want 11", changes)
+	}
+}
+
+func TestSelectionExtend(t *testing.T) {
+	var s Selection
+	s.Set(5, true)
+	s.Click(2, 0, nil)
+	s.Extend(4, nil)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("Extend selected %v", got)
+	}
+	s.Extend(0, nil)
+	if got := s.Indices(); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("Extend selected %v", got)
+	}
+}
@@ -35,9 +35,12 @@ func Slider(th *Theme, value *float32, minV, maxV float32, options ...Option) *S
 	}
 	s.Value = value
 	s.th = th
-	s.SetupTabs()
+	s.SetupTabs(th.form)
 	s.width = unit.Dp(99999)
 	s.Apply(options...)
+	if old, ok := th.form.keep(s.key, &s).(*SliderStyle); ok {
+		s.Clickable.keep(&old.Clickable)
+	}
 	return &s
 }
 
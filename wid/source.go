@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"image"
+	"reflect"
+	"sync"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+// DataSource gives the rows of a table that are too many, or too slow to get, to be kept in a slice,
+// like the rows of a database or the memory of a debugger target.
+// Count and Fetch are called from goroutines, not from the one drawing the table.
+type DataSource interface {
+	// Count returns the number of rows.
+	Count() (int, error)
+	// Fetch returns n rows from offset, or fewer at the end. Each row is a struct, or a pointer to a struct,
+	// of the element type of the data given to the table.
+	Fetch(offset, n int) ([]interface{}, error)
+	// Notify sets the function the source calls, from any goroutine, when the rows have changed.
+	Notify(changed func())
+}
+
+const (
+	// pageSize is the number of rows fetched at a time, and maxPages is the number of pages kept.
+	pageSize = 100
+	maxPages = 20
+	// retryDelay is the time before a page that failed is fetched again. It is doubled for each failure,
+	// up to maxRetryDelay.
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// pager fetches the pages of rows from a data source in goroutines, and keeps the pages fetched.
+// The rows are handed over to the table in update, when it is drawn.
+type pager struct {
+	src        DataSource
+	invalidate func()
+	typ        reflect.Type
+	// pages and loading is the pages fetched and the pages being fetched, and retries is the pages that failed.
+	// They, and count, are only used by the table, while the fields below mu are set by the goroutines.
+	pages   map[int][]reflect.Value
+	loading map[int]bool
+	retries map[int]retry
+	count   int
+	last    int
+	mu      sync.Mutex
+	arrived map[int][]reflect.Value
+	failed  map[int]bool
+	counted bool
+	n       int
+	stale   bool
+	// gen is incremented when the rows change, so that rows fetched before the change are dropped.
+	gen int
+	err error
+	// exporting is set while all rows are fetched for an export, and export is the rows, or the error,
+	// when they have arrived.
+	exporting bool
+	export    *fetchResult
+}
+
+// fetchResult is all the rows of the source, or the error that stopped the fetching.
+type fetchResult struct {
+	rows []reflect.Value
+	err  error
+}
+
+// retry is the number of times a page has failed, and the time when it may be fetched again.
+type retry struct {
+	n  int
+	at time.Time
+}
+
+// Source is an option parameter that makes the table get its rows from src, a page at a time. The data given
+// to the table is then only used for its element type, and can be a nil slice. Rows not fetched yet are shown
+// as placeholders. invalidate is called from the goroutines when rows arrive, to get the window drawn again,
+// and will usually be the Invalidate method of the window.
+// Source tables can not be sorted, filtered, grouped or made into trees.
+func Source(src DataSource, invalidate func()) TableOption {
+	return func(t *TableDef) {
+		t.pager = &pager{src: src, invalidate: invalidate}
+	}
+}
+
+// Err returns the last error from the data source of the table, or nil. Pages that could not be fetched
+// are fetched again after a delay, which grows each time they fail.
+func (t *TableDef) Err() error {
+	if t.pager == nil {
+		return nil
+	}
+	t.pager.mu.Lock()
+	defer t.pager.mu.Unlock()
+	return t.pager.err
+}
+
+// sameSource returns true if a and b is the same source.
+func sameSource(a, b DataSource) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b
+}
+
+// start begins fetching from the source, for rows of type typ.
+func (p *pager) start(typ reflect.Type) {
+	p.typ = typ
+	p.pages = make(map[int][]reflect.Value)
+	p.loading = make(map[int]bool)
+	p.retries = make(map[int]retry)
+	p.arrived = make(map[int][]reflect.Value)
+	p.failed = make(map[int]bool)
+	p.src.Notify(func() {
+		p.mu.Lock()
+		p.stale = true
+		p.mu.Unlock()
+		p.invalidate()
+	})
+	p.fetchCount(0)
+}
+
+// fetchCount gets the number of rows. If it fails, it is tried again after the same delays as a page,
+// until it succeeds or the rows change.
+func (p *pager) fetchCount(gen int) {
+	go func() {
+		for tries := 0; ; tries++ {
+			n, err := p.src.Count()
+			p.mu.Lock()
+			current := gen == p.gen
+			if current && err == nil {
+				p.n, p.counted = n, true
+			} else if current {
+				p.err = err
+			}
+			p.mu.Unlock()
+			p.invalidate()
+			if err == nil || !current {
+				return
+			}
+			time.Sleep(retryAfter(tries))
+		}
+	}()
+}
+
+// retryAfter returns the delay before fetching again after n failures, which is retryDelay doubled
+// for each failure, up to maxRetryDelay.
+func retryAfter(n int) time.Duration {
+	d := retryDelay
+	for i := 0; i < n && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d
+}
+
+// want starts fetching page, if it is not fetched already. A page that failed is fetched again when
+// its retry delay has passed.
+func (p *pager) want(page int) {
+	p.last = page
+	if p.pages[page] != nil || p.loading[page] || time.Now().Before(p.retries[page].at) {
+		return
+	}
+	p.loading[page] = true
+	gen := p.gen
+	go func() {
+		rows, err := p.src.Fetch(page*pageSize, pageSize)
+		values := structs(rows)
+		p.mu.Lock()
+		if gen == p.gen && err == nil {
+			p.arrived[page] = values
+		} else if gen == p.gen {
+			p.err = err
+			p.failed[page] = true
+		}
+		p.mu.Unlock()
+		p.invalidate()
+	}()
+}
+
+// structs returns the structs of the rows fetched.
+func structs(rows []interface{}) []reflect.Value {
+	values := make([]reflect.Value, len(rows))
+	for i, r := range rows {
+		v := reflect.ValueOf(r)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		} else {
+			// Make a copy that can be addressed, like the elements of a slice.
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			v = c
+		}
+		values[i] = v
+	}
+	return values
+}
+
+// fetchExport starts fetching all the rows for an export, if it is not started already.
+func (p *pager) fetchExport() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exporting {
+		return
+	}
+	p.exporting = true
+	go func() {
+		rows, err := p.fetchAll()
+		p.mu.Lock()
+		p.export = &fetchResult{rows: rows, err: err}
+		p.mu.Unlock()
+		p.invalidate()
+	}()
+}
+
+// exported returns the rows fetched for an export, and true if they have arrived.
+func (p *pager) exported() ([]reflect.Value, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := p.export
+	if r == nil {
+		return nil, false, nil
+	}
+	p.exporting, p.export = false, nil
+	return r.rows, true, r.err
+}
+
+// fetchAll fetches all the rows from the source, a page at a time, without keeping them in the pages.
+// It is called from a goroutine, as it waits for the source.
+func (p *pager) fetchAll() ([]reflect.Value, error) {
+	var values []reflect.Value
+	for {
+		rows, err := p.src.Fetch(len(values), pageSize)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, structs(rows)...)
+		if len(rows) < pageSize {
+			return values, nil
+		}
+	}
+}
+
+// update takes over the rows and the count fetched since the last update. It returns true if anything changed.
+func (p *pager) update() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := false
+	if p.stale {
+		p.stale, p.counted = false, false
+		p.gen++
+		p.err = nil
+		p.pages = make(map[int][]reflect.Value)
+		p.loading = make(map[int]bool)
+		p.retries = make(map[int]retry)
+		p.arrived = make(map[int][]reflect.Value)
+		p.failed = make(map[int]bool)
+		p.fetchCount(p.gen)
+		changed = true
+	}
+	if p.counted {
+		p.count, p.counted = p.n, false
+		changed = true
+	}
+	for page, rows := range p.arrived {
+		p.pages[page] = rows
+		delete(p.loading, page)
+		delete(p.retries, page)
+		changed = true
+	}
+	p.arrived = make(map[int][]reflect.Value)
+	// A page that failed is fetched again after a delay, doubled each time it fails. The window is
+	// drawn again then, so that want is called for it.
+	for page := range p.failed {
+		delete(p.loading, page)
+		r := p.retries[page]
+		delay := retryAfter(r.n)
+		r.n++
+		r.at = time.Now().Add(delay)
+		p.retries[page] = r
+		time.AfterFunc(delay, p.invalidate)
+		changed = true
+	}
+	p.failed = make(map[int]bool)
+	// Drop the pages farthest from the one wanted last.
+	for len(p.pages) > maxPages {
+		far := p.last
+		for page := range p.pages {
+			if abs(page-p.last) > abs(far-p.last) {
+				far = page
+			}
+		}
+		delete(p.pages, far)
+	}
+	return changed
+}
+
+// loaded returns true if row i is fetched.
+func (p *pager) loaded(i int) bool {
+	return i%pageSize < len(p.pages[i/pageSize])
+}
+
+// elem returns the struct of row i, or a new zero struct if it is not fetched.
+func (p *pager) elem(i int) reflect.Value {
+	if !p.loaded(i) {
+		return reflect.New(p.typ).Elem()
+	}
+	return p.pages[i/pageSize][i%pageSize]
+}
+
+// placeholder returns a row shown while row i is fetched, with a gray bar in each cell.
+func (t *TableDef) placeholder(i int) layout.Widget {
+	var cells []layout.Widget
+	if t.selField != nil {
+		cells = append(cells, empty)
+	}
+	bar := func(gtx C) D {
+		return t.thg.LabelPadding.Layout(gtx, func(gtx C) D {
+			h := gtx.Px(t.th.TextSize)
+			size := image.Pt(gtx.Constraints.Max.X*2/3, h)
+			r := float32(h) / 4
+			paint.FillShape(gtx.Ops, MulAlpha(t.th.OnBackground, 30),
+				clip.UniformRRect(f32.Rectangle{Max: layout.FPt(size)}, r).Op(gtx.Ops))
+			return D{Size: image.Pt(gtx.Constraints.Max.X, h)}
+		})
+	}
+	for range t.columns {
+		cells = append(cells, bar)
+	}
+	return Col(t.tableRow(&t.thg, nil, cells...), Separator(t.th, unit.Dp(0.5), W(9999)))
+}
+
+// SliceSource is a DataSource with the rows kept in a slice, used for testing tables with a source.
+// Delay is added to each call, to act like a slow backend.
+type SliceSource struct {
+	Delay  time.Duration
+	mu     sync.Mutex
+	data   reflect.Value
+	notify func()
+}
+
+// NewSliceSource returns a source with the rows in data, which is a slice of structs or pointers to structs.
+func NewSliceSource(data interface{}) *SliceSource {
+	s := &SliceSource{}
+	s.data = reflect.ValueOf(data)
+	return s
+}
+
+// Set replaces the rows with data, and notifies the table.
+func (s *SliceSource) Set(data interface{}) {
+	s.mu.Lock()
+	s.data = reflect.ValueOf(data)
+	notify := s.notify
+	s.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+}
+
+// Count returns the number of rows.
+func (s *SliceSource) Count() (int, error) {
+	time.Sleep(s.Delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Len(), nil
+}
+
+// Fetch returns n rows from offset.
+func (s *SliceSource) Fetch(offset, n int) ([]interface{}, error) {
+	time.Sleep(s.Delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []interface{}
+	for i := offset; i < offset+n && i < s.data.Len(); i++ {
+		e := s.data.Index(i)
+		if e.Kind() != reflect.Ptr {
+			e = e.Addr()
+		}
+		rows = append(rows, e.Interface())
+	}
+	return rows, nil
+}
+
+// Notify sets the function called when the rows are changed by Set.
+func (s *SliceSource) Notify(changed func()) {
+	s.mu.Lock()
+	s.notify = changed
+	s.mu.Unlock()
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testSource is a SliceSource that counts the fetches, can fail the first ones and can hold them
+// until release is closed. failCount is the number of counts that fail.
+type testSource struct {
+	*SliceSource
+	fetches   int32
+	fail      int32
+	failCount int32
+	release   chan struct{}
+}
+
+func (s *testSource) Count() (int, error) {
+	if atomic.AddInt32(&s.failCount, -1) >= 0 {
+		return 0, errors.New("backend is down")
+	}
+	return s.SliceSource.Count()
+}
+
+func (s *testSource) Fetch(offset, n int) ([]interface{}, error) {
+	atomic.AddInt32(&s.fetches, 1)
+	if s.release != nil {
+		<-s.release
+	}
+	if atomic.AddInt32(&s.fail, -1) >= 0 {
+		return nil, errors.New("backend is down")
+	}
+	return s.SliceSource.Fetch(offset, n)
+}
+
+// newTestPager returns a started pager for src, with the count fetched, and a function that updates the
+// pager until done returns true.
+func newTestPager(t *testing.T, src DataSource) (*pager, func(done func() bool)) {
+	ready := make(chan struct{}, 1)
+	p := &pager{src: src, invalidate: func() {
+		select {
+		case ready <- struct{}{}:
+		default:
+		}
+	}}
+	p.start(reflect.TypeOf(fruit{}))
+	wait := func(done func() bool) {
+		t.Helper()
+		for p.update(); !done(); p.update() {
+			select {
+			case <-ready:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the source did not answer")
+			}
+		}
+	}
+	wait(func() bool { return p.count > 0 })
+	return p, wait
+}
+
+func TestPagerCache(t *testing.T) {
+	src := &testSource{SliceSource: NewSliceSource(append([]fruit(nil), fruits...))}
+	p, wait := newTestPager(t, src)
+	if p.count != len(fruits) {
+		t.Fatalf("count is %d, want %d", p.count, len(fruits))
+	}
+	if p.loaded(0) || p.elem(0).Field(0).String() != "" {
+		t.Errorf("row 0 is loaded before it is wanted")
+	}
+	p.want(0)
+	p.want(0)
+	wait(func() bool { return p.loaded(0) })
+	if name := p.elem(2).Field(0).String(); !p.loaded(2) || name != "Cherry" {
+		t.Errorf("row 2 is %q, want Cherry", name)
+	}
+	p.want(0)
+	if n := atomic.LoadInt32(&src.fetches); n != 1 {
+		t.Errorf("the page was fetched %d times, want once", n)
+	}
+}
+
+func TestPagerGen(t *testing.T) {
+	src := &testSource{SliceSource: NewSliceSource(append([]fruit(nil), fruits...)), release: make(chan struct{})}
+	p, wait := newTestPager(t, src)
+	p.want(0)
+	// The rows change while the page is fetched, so the page fetched is dropped.
+	src.Set(fruits[:1])
+	wait(func() bool { return p.gen == 1 })
+	close(src.release)
+	wait(func() bool { return p.count == 1 })
+	time.Sleep(50 * time.Millisecond)
+	p.update()
+	if p.loaded(0) {
+		t.Errorf("a page fetched before the change was kept")
+	}
+	p.want(0)
+	wait(func() bool { return p.loaded(0) })
+	if p.loaded(1) {
+		t.Errorf("the page fetched after the change has %d rows, want 1", len(p.pages[0]))
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	src := &testSource{SliceSource: NewSliceSource(append([]fruit(nil), fruits...)), fail: 1}
+	p, wait := newTestPager(t, src)
+	p.want(0)
+	wait(func() bool { return !p.loading[0] })
+	if p.loading[0] || p.loaded(0) || p.err == nil {
+		t.Fatalf("after a failed fetch loading is %v and the error %v", p.loading[0], p.err)
+	}
+	p.want(0)
+	if n := atomic.LoadInt32(&src.fetches); n != 1 {
+		t.Errorf("the page was fetched %d times before the retry delay, want once", n)
+	}
+	r := p.retries[0]
+	if r.n != 1 || time.Until(r.at) > retryDelay {
+		t.Errorf("the retry is %+v", r)
+	}
+	// Let the delay pass.
+	p.retries[0] = retry{n: r.n}
+	p.want(0)
+	wait(func() bool { return p.loaded(0) })
+	if len(p.retries) != 0 {
+		t.Errorf("the page is not fetched on retry")
+	}
+}
+
+func TestPagerCountError(t *testing.T) {
+	src := &testSource{SliceSource: NewSliceSource(append([]fruit(nil), fruits...)), failCount: 1}
+	p, _ := newTestPager(t, src)
+	if p.count != len(fruits) || p.err == nil {
+		t.Errorf("after a failed count the count is %d and the error %v", p.count, p.err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for n, want := range []time.Duration{retryDelay, 2 * retryDelay, 4 * retryDelay} {
+		if d := retryAfter(n); d != want {
+			t.Errorf("retryAfter(%d) = %v, want %v", n, d, want)
+		}
+	}
+	if d := retryAfter(100); d != maxRetryDelay {
+		t.Errorf("retryAfter(100) = %v, want %v", d, maxRetryDelay)
+	}
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+)
+
+// fieldTag is the parsed wid tag of a struct field.
+type fieldTag struct {
+	name     string
+	label    string
+	width    float32
+	format   string
+	options  []string
+	readonly bool
+	required bool
+}
+
+// StructForm returns a form with one widget for each exported field of the struct pointed to by v.
+// Strings and numbers are shown as edits, bools as checkboxes, integers with options as dropdowns and
+// sub-structs as sections with a heading. All widgets are bound to the fields.
+// The widgets are set up with the wid tag of the fields, which is a comma separated list like
+// `wid:"label=Name,width=200,required"`. Label is the text shown (default is the field name),
+// width is the width as given to W(), readonly shows the value as text, required makes an empty
+// value invalid, format is the fmt format string for floats and options is a list of values for
+// an integer of any kind, like options=Off|On. Required can only be given for fields shown as edits.
+// Fields with the tag `wid:"-"` are skipped.
+// If a Key option is given, the fields get keys made from the key and the field names, so that
+// their state is kept when the form is rebuilt.
+// StructForm panics if v is not a pointer to a struct, or if required is given for another field.
+func StructForm(th *Theme, v interface{}, options ...Option) layout.Widget {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		panic("wid: StructForm needs a pointer to a struct")
+	}
+	var w Widget
+	w.Apply(options...)
+	return Col(structFields(th, rv.Elem(), w.key)...)
+}
+
+// structFields returns the widgets for the fields of the struct v.
+func structFields(th *Theme, v reflect.Value, key string) []layout.Widget {
+	var widgets []layout.Widget
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("wid") == "-" {
+			continue
+		}
+		if w := structField(th, v.Field(i), parseTag(f), key); w != nil {
+			widgets = append(widgets, w)
+		}
+	}
+	return widgets
+}
+
+// structField returns the widget for the field v, or nil if the type of the field is not supported.
+func structField(th *Theme, v reflect.Value, tag fieldTag, key string) layout.Widget {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var options []Option
+	if key != "" {
+		key = key + "." + tag.name
+		options = append(options, Key(key))
+	}
+	if tag.width != 0 {
+		options = append(options, W(tag.width))
+	}
+	b := reflectBinding{v: v, fmt: tag.format}
+	// Only edits can check that a value is given.
+	if tag.required && (tag.readonly || len(tag.options) > 0 || v.Kind() == reflect.Struct || v.Kind() == reflect.Bool) {
+		panic("wid: required is only supported for editable text and number fields, not for " + tag.name)
+	}
+	switch {
+	case v.Kind() == reflect.Struct:
+		return Col(
+			Label(th, tag.label, Bold()),
+			Pad(layout.Inset{Left: th.TextSize}, Col(structFields(th, v, key)...)),
+		)
+	case v.Kind() == reflect.Bool:
+		b := v.Addr().Convert(reflect.TypeOf((*bool)(nil))).Interface().(*bool)
+		if tag.readonly {
+			return labelled(th, tag.label, Value(th, func() string { return strconv.FormatBool(*b) }))
+		}
+		return Checkbox(th, tag.label, b, nil, options...)
+	case len(tag.options) > 0 && isInteger(v.Kind()):
+		return labelled(th, tag.label, optionsField(th, v, tag, options...))
+	}
+	if !b.supported() {
+		return nil
+	}
+	if tag.readonly {
+		return labelled(th, tag.label, Value(th, b.format))
+	}
+	options = append(options, Lbl(tag.label), EditOption(func(e *EditDef) { e.value = b }))
+	if tag.required {
+		options = append(options, Required())
+	}
+	return Edit(th, options...)
+}
+
+// isInteger returns true if k is a signed or unsigned integer kind.
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// optionsField returns a dropdown for the integer field v, of any integer kind, where value i is option i.
+func optionsField(th *Theme, v reflect.Value, tag fieldTag, options ...Option) layout.Widget {
+	if tag.readonly {
+		b := reflectBinding{v: v}
+		return Value(th, func() string {
+			if i, err := strconv.Atoi(b.format()); err == nil && i >= 0 && i < len(tag.options) {
+				return tag.options[i]
+			}
+			return b.format()
+		})
+	}
+	return DropDownItems(th, v.Addr().Interface(), optionItems(v.Type(), tag.options), options...).Layout
+}
+
+// optionItems returns the items of the option names, with the index of each as key, of type typ.
+func optionItems(typ reflect.Type, names []string) []Item {
+	var items []Item
+	for i, o := range names {
+		items = append(items, Item{Key: reflect.ValueOf(i).Convert(typ).Interface(), Text: o})
+	}
+	return items
+}
+
+// parseTag returns the settings from the wid tag of the field f.
+func parseTag(f reflect.StructField) fieldTag {
+	tag := fieldTag{name: f.Name, label: f.Name}
+	for _, part := range strings.Split(f.Tag.Get("wid"), ",") {
+		name, value := strings.TrimSpace(part), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, value = name[:i], name[i+1:]
+		}
+		switch name {
+		case "label":
+			tag.label = value
+		case "width":
+			w, _ := strconv.ParseFloat(value, 32)
+			tag.width = float32(w)
+		case "format":
+			tag.format = value
+		case "options":
+			tag.options = strings.Split(value, "|")
+		case "readonly":
+			tag.readonly = true
+		case "required":
+			tag.required = true
+		}
+	}
+	return tag
+}
+
+// labelled lays out w with a label in front, aligned with the labels of edits.
+func labelled(th *Theme, label string, w layout.Widget) layout.Widget {
+	return func(gtx C) D {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start}.Layout(
+			gtx,
+			layout.Rigid(func(gtx C) D {
+				p := th.EditPadding
+				p.Top = unit.Dp(p.Top.V + th.LabelPadding.Top.V)
+				return p.Layout(gtx, func(gtx C) D {
+					gtx.Constraints.Min.X = gtx.Px(th.TextSize.Scale(6))
+					paint.ColorOp{Color: th.OnBackground}.Add(gtx.Ops)
+					return aLabel{Alignment: text.End}.Layout(gtx, th.Shaper, text.Font{}, th.TextSize, label)
+				})
+			}),
+			layout.Flexed(1, w),
+		)
+	}
+}
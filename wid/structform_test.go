@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type level uint8
+
+func TestStructFormOptions(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	v := struct {
+		Small int8  `wid:"options=Off|On"`
+		Level level `wid:"options=Low|Mid|High"`
+		Text  string
+	}{Level: 2}
+	fields := reflect.ValueOf(&v).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		f := fields.Type().Field(i)
+		if w := structField(th, fields.Field(i), parseTag(f), ""); w == nil {
+			t.Errorf("no widget for %s", f.Name)
+		}
+	}
+	// Choosing an option writes its index, converted to the type of the field.
+	for _, p := range []interface{}{&v.Small, &v.Level} {
+		f := reflect.ValueOf(p).Elem()
+		b := DropDownItems(th, p, optionItems(f.Type(), []string{"Off", "On"}))
+		b.load()
+		b.selected = 1
+		b.store()
+		if fmt.Sprint(f.Interface()) != "1" {
+			t.Errorf("choosing the second option set the %s field to %v, want 1", f.Type(), f.Interface())
+		}
+	}
+}
+
+func TestStructFormRequired(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	for _, v := range []interface{}{
+		&struct {
+			Paid bool `wid:"required"`
+		}{},
+		&struct {
+			Mode int `wid:"options=A|B,required"`
+		}{},
+		&struct {
+			ID int `wid:"readonly,required"`
+		}{},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("required was accepted for %T", v)
+				}
+			}()
+			StructForm(th, v)
+		}()
+	}
+	StructForm(th, &struct {
+		Name string `wid:"required"`
+	}{})
+}
@@ -28,7 +28,7 @@ type SwitchDef struct {
 func Switch(th *Theme, State *bool, handler func(b bool)) func(gtx C) D {
 	s := &SwitchDef{}
 	s.th = th
-	s.SetupTabs()
+	s.SetupTabs(th.form)
 	s.Value = State
 	s.handler = handler
 	s.padding = layout.Inset{Top: unit.Dp(5), Bottom: unit.Dp(5), Left: unit.Dp(5), Right: unit.Dp(5)}
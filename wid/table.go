@@ -0,0 +1,1125 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/clipboard"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+)
+
+// Column defines one column of a table.
+type Column struct {
+	// Title is the text in the header.
+	Title string
+	// Field is the name of the struct field shown in the column. The table can be sorted on it.
+	Field string
+	// Width is the column width. Values up to 1.0 are a fraction of the table width, and larger values
+	// are a fixed width, as for the weights of a Row.
+	Width float32
+	// MinWidth is the smallest width the user can drag the column to, in the same units as a fixed Width.
+	// The default is 4.
+	MinWidth float32
+	// Format returns the text shown for the field value v. The default is fmt.Sprint(v).
+	Format func(v interface{}) string
+	// Cell returns the widget for the row element p, which is a pointer to the struct.
+	// It is used to show other widgets than labels, like a dropdown. The default is a label with the formatted field.
+	Cell func(th *Theme, p interface{}) layout.Widget
+	// Editable lets the user edit the field in place, with double click, Enter or F2 on the cell,
+	// and paste into it with Ctrl-V. Strings and numbers are edited as text, and bools are toggled.
+	Editable bool
+	// Options is the names of the values of an integer field, of any kind, shown instead of the number.
+	Options []string
+	// Filter adds a filter for the column in a row under the header. Numbers are filtered on a range,
+	// columns with Options on one of the options, and other fields on the text they contain.
+	Filter bool
+	// Aggregate is computed from the numbers in the column, for the rows found by the filters, and shown in
+	// a footer under the table, and under each group when the rows are grouped.
+	Aggregate Aggregate
+}
+
+// TableDef is a table showing a slice of structs, with one row for each element.
+type TableDef struct {
+	Widget
+	Clickable
+	data reflect.Value
+	// all is the columns given to Table, and order and visible is the order and visibility of them set
+	// by the user. columns is the columns shown, and shown is the index in all of each of them.
+	all     []Column
+	order   []int
+	visible []bool
+	columns []Column
+	shown   []int
+	// allWidths and allFilters is the width and filter of each column in all, kept while it is hidden.
+	allWidths  []int
+	allFilters []colFilter
+	// fields is the index of the struct field of each column, nil if the column has no field.
+	fields [][]int
+	// selField is the index of the bool field that holds the selection, nil if there is no such field.
+	selField []int
+	sel      *Selection
+	// view is the indices of the elements in the order they are shown.
+	view    []int
+	sortCol int
+	sortUp  bool
+	// selectAll is the state of the header checkbox. It is found again at the next layout when
+	// selChanged is set, after the selection or the rows found are changed.
+	selectAll  CheckState
+	selChanged bool
+	thh        Theme
+	thg        Theme
+	header     []*ButtonDef
+	heading    layout.Widget
+	list       *lazyList
+	upIcon     *Icon
+	downIcon   *Icon
+	// children and hasChildren is set for a tree table, where nodes is the rows that are loaded.
+	children     func(p interface{}) interface{}
+	hasChildren  func(p interface{}) bool
+	nodes        []treeNode
+	expandIcon   *Icon
+	collapseIcon *Icon
+	// groupField is the index of the field the rows are grouped on, nil if they are not grouped.
+	// lines is the headers, rows and footers in the list, and groupOf is the group of each row.
+	groupField []int
+	groups     []group
+	groupOf    map[int]int
+	lines      []listLine
+	collapsed  map[string]bool
+	// totals is the numbers for the total footer, nil when they must be computed again. They, and the
+	// numbers of the groups, are dropped by Changed and Refresh.
+	totals   []stats
+	totalRow layout.Widget
+	// pager gets the rows from a data source, nil if they are in data.
+	pager *pager
+	// page and pageSize is the page of rows shown, nil if all rows are shown, and shownStart is the first line of it.
+	page       *int
+	pageSize   *int
+	shownStart int
+	// pending is changes to the rows that are made before the next layout, not while the list is drawn.
+	pending []func()
+	// widths is the width in pixels of each cell in a row, including the checkbox column.
+	widths []int
+	// frozen is the number of leading columns that stay at the left when the table is scrolled horizontally.
+	frozen int
+	// sized is the widths set by dragging the header borders, nil until a column is resized.
+	sized     []int
+	handles   []colHandle
+	dragStart float32
+	// unit is the pixels for one unit of a fixed Column.Width, at the last layout.
+	unit float32
+	// movers is the drag areas over the header cells, and moveCol is the column being moved, or -1.
+	movers  []gesture.Drag
+	moveCol int
+	// menu is the column menu, opened at menuPos by a secondary click on the header.
+	menu     layout.Widget
+	menuOpen bool
+	menuPos  image.Point
+	// curRow and curCol is the cell cursor, as an index into view and columns.
+	curRow int
+	curCol int
+	// blockRow and blockCol is the cell where the cursor was last moved to without Shift. When block is set,
+	// the cells from there to the cursor are marked, and are copied with Ctrl-C.
+	blockRow int
+	blockCol int
+	block    bool
+	// editor is the edit used for the cell at the cursor, nil when no cell is edited, and editID is its row.
+	editor      *EditDef
+	editID      int
+	editValue   binding
+	editFocused bool
+	// filters is the filter of each column, and search is text that must be found in one of the columns.
+	filters []colFilter
+	search  string
+	// dirty is set when the search text or a filter is changed after the view was made, and total is
+	// the number of elements when the view was made.
+	dirty bool
+	total int
+	// exportName is the file written by the export shortcut and button, and exportDone is called after it is written.
+	exportName string
+	exportDone func(err error)
+	// pasteDone is called after text is pasted.
+	pasteDone func(err error)
+}
+
+// colHandle is the draggable border at the right side of a header cell.
+type colHandle struct {
+	drag  gesture.Drag
+	click gesture.Click
+}
+
+// TableOption is options specific to tables.
+type TableOption func(*TableDef)
+
+// Table returns a table showing data, which is a slice of structs (or pointers to structs), or a pointer to
+// such a slice if elements are added or removed later. Each column shows a field or a widget made by
+// the column's Cell function. Clicking a header sorts the rows on that column, without changing data.
+// The header stays at the top when the rows are scrolled. Header cells can be dragged to move the columns,
+// and a secondary click on the header opens a menu where columns are hidden and shown, see ColumnLayout.
+func Table(th *Theme, a AnchorStrategy, data interface{}, columns []Column, options ...Option) *TableDef {
+	t := &TableDef{all: columns, sortCol: -1, moveCol: -1, collapsed: make(map[string]bool)}
+	t.th = th
+	t.data = reflect.ValueOf(data)
+	if t.data.Kind() == reflect.Ptr {
+		t.data = t.data.Elem()
+	}
+	if t.data.Kind() != reflect.Slice {
+		panic("wid: Table needs a slice")
+	}
+	t.upIcon, _ = NewIcon(icons.NavigationArrowUpward)
+	t.downIcon, _ = NewIcon(icons.NavigationArrowDownward)
+	t.expandIcon, _ = NewIcon(icons.NavigationChevronRight)
+	t.collapseIcon, _ = NewIcon(icons.NavigationExpandMore)
+	for _, option := range options {
+		option.apply(t)
+	}
+	if t.pager != nil {
+		t.nodes = nil
+	}
+	typ := t.data.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	// Setup theme for heading. The copies belong to the form of th, so the header and row widgets
+	// are kept and detached by the same form as the table.
+	t.thh = *th
+	t.thh.OnBackground = WithAlpha(th.Primary, 210)
+	t.thh.Background = th.Surface
+	t.thh.LabelPadding = layout.UniformInset(th.TextSize.Scale(0.4))
+	// Setup theme for grid labels.
+	t.thg = *th
+	t.thg.Background = th.Surface
+	t.thg.LabelPadding = layout.UniformInset(th.TextSize.Scale(0.35))
+	t.visible = make([]bool, len(columns))
+	for i := range columns {
+		t.order = append(t.order, i)
+		t.visible[i] = true
+	}
+	t.allWidths = make([]int, len(columns))
+	t.allFilters = make([]colFilter, len(columns))
+	// The column layout and the filters are taken over before the filter widgets are bound to them.
+	old, _ := th.form.keep(t.key, t).(*TableDef)
+	if old != nil && len(old.all) == len(columns) {
+		t.order, t.visible = old.order, old.visible
+		t.allWidths, t.allFilters = old.allWidths, old.allFilters
+	}
+	t.showColumns()
+	t.filters = make([]colFilter, len(t.columns))
+	if old != nil && len(old.filters) == len(t.filters) {
+		copy(t.filters, old.filters)
+		t.search = old.search
+	}
+	t.heading = t.makeHeading(th)
+	t.SetupTabs(th.form)
+	t.list = newLazyList(&t.thg, a, t.listLen, t.row)
+	t.list.rowKey = t.rowKey
+	if old != nil {
+		t.list.scope = old.list.scope
+		t.sortCol = old.sortCol
+		t.sortUp = old.sortUp
+		t.selectAll = old.selectAll
+		t.sized = old.sized
+		t.unit = old.unit
+		t.shownStart = old.shownStart
+		t.curRow = old.curRow
+		t.curCol = old.curCol
+		if t.nodes != nil && old.nodes != nil {
+			t.nodes = old.nodes
+		}
+		t.collapsed = old.collapsed
+		if t.pager != nil && old.pager != nil && sameSource(t.pager.src, old.pager.src) {
+			t.pager = old.pager
+		}
+		t.Clickable.keep(&old.Clickable)
+		t.list.keep(&old.list.ListStyle)
+		if t.sel == nil {
+			t.sel = old.sel
+		}
+	}
+	if t.sel == nil {
+		t.sel = &Selection{}
+	}
+	if t.pager != nil && t.pager.pages == nil {
+		t.pager.start(typ)
+	}
+	if t.selField != nil {
+		t.sel.changed = func(i int, selected bool) {
+			t.elem(i).FieldByIndex(t.selField).SetBool(selected)
+			t.selChanged = true
+		}
+	}
+	t.Refresh()
+	return t
+}
+
+// SelectedField is an option parameter that gives the name of a bool field in the struct, that is set
+// when the row is selected. The table then gets a column of checkboxes, and a checkbox in the header
+// that selects all rows.
+func SelectedField(name string) TableOption {
+	return func(t *TableDef) {
+		typ := t.data.Type().Elem()
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		t.selField = fieldIndex(typ, name)
+	}
+}
+
+// FrozenColumns is an option parameter that keeps the first n columns, and the checkbox column,
+// at the left side when the table is scrolled horizontally.
+func FrozenColumns(n int) TableOption {
+	return func(t *TableDef) {
+		t.frozen = n
+	}
+}
+
+// Selection returns the selected rows, given by their index in the data.
+func (t *TableDef) Selection() *Selection {
+	return t.sel
+}
+
+func (t *TableDef) setSelection(s *Selection) {
+	t.sel = s
+}
+
+func (o TableOption) apply(cfg interface{}) {
+	o(cfg.(*TableDef))
+}
+
+// fieldIndex returns the index of the field name in the struct type typ, or nil if name is empty.
+func fieldIndex(typ reflect.Type, name string) []int {
+	if name == "" {
+		return nil
+	}
+	f, ok := typ.FieldByName(name)
+	if !ok {
+		panic(fmt.Sprintf("wid: %s has no field %s", typ.Name(), name))
+	}
+	// The values of unexported fields can not be read with Interface, which the table needs.
+	if f.PkgPath != "" {
+		panic(fmt.Sprintf("wid: field %s of %s is not exported", name, typ.Name()))
+	}
+	return f.Index
+}
+
+// Refresh updates the table after the data is changed, filtering and sorting the rows again.
+// If the table has a selected field, the selection is read from it.
+func (t *TableDef) Refresh() {
+	t.dirty = false
+	t.total = t.length()
+	n := t.total
+	if t.nodes != nil {
+		t.makeNodes(n)
+		n = len(t.nodes)
+	}
+	if t.selField != nil && t.pager == nil {
+		t.sel.read(n, func(i int) bool { return t.elem(i).FieldByIndex(t.selField).Bool() })
+	}
+	t.view = t.view[:0]
+	if t.nodes == nil {
+		for i := 0; i < t.total; i++ {
+			if t.pager != nil || t.match(t.elem(i)) {
+				t.view = append(t.view, i)
+			}
+		}
+	}
+	// The aggregates are computed again from the new groups, or from the view.
+	t.totals = nil
+	if t.grouped() {
+		t.makeGroups()
+	}
+	t.sort()
+	t.curRow = clampInt(t.curRow, 0, len(t.view)-1)
+	t.selChanged = t.selField != nil
+}
+
+// Count returns the number of rows found by the filters, and the number of elements in the data.
+func (t *TableDef) Count() (visible, total int) {
+	if t.grouped() {
+		for _, g := range t.groups {
+			visible += len(g.rows)
+		}
+		return visible, t.length()
+	}
+	return len(t.view), t.length()
+}
+
+// length returns the number of elements in the data, or the number of rows of the data source.
+func (t *TableDef) length() int {
+	if t.pager != nil {
+		return t.pager.count
+	}
+	return t.data.Len()
+}
+
+// later makes f be called at the start of the next layout.
+func (t *TableDef) later(f func()) {
+	t.pending = append(t.pending, f)
+}
+
+// Layout draws the table.
+func (t *TableDef) Layout(gtx C) D {
+	if t.pager != nil && t.pager.update() {
+		t.Refresh()
+	}
+	if t.pager != nil {
+		t.finishExport()
+	}
+	if t.length() != t.total || t.dirty {
+		t.Refresh()
+	}
+	t.unit = float32(gtx.Px(t.th.TextSize)) / 2
+	for _, f := range t.pending {
+		f()
+	}
+	t.pending = t.pending[:0]
+	t.layoutWidths(gtx)
+	t.resizing(gtx)
+	if t.moving(gtx) {
+		t.layoutWidths(gtx)
+	}
+	t.handleKeys(gtx)
+	t.showPage()
+	if t.selChanged {
+		t.selectAll = t.selectState()
+		t.selChanged = false
+	}
+	if t.editor != nil {
+		// Clicking outside the editor will commit a valid value.
+		if t.editFocused && !t.editor.Focused() {
+			if t.editValue.parse(t.editor.Text()) == nil {
+				t.Changed(t.editID)
+			}
+			t.editor = nil
+		} else {
+			t.editFocused = t.editor.Focused()
+		}
+	}
+	for i, b := range t.header {
+		if b == nil {
+			continue
+		}
+		b.Icon = nil
+		if i == t.sortCol && t.sortUp {
+			b.Icon = t.upIcon
+		} else if i == t.sortCol {
+			b.Icon = t.downIcon
+		}
+	}
+	dims := layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(t.layoutHeading),
+		layout.Flexed(1, t.list.Layout),
+		layout.Rigid(t.layoutFooter),
+	)
+	if len(t.pending) > 0 {
+		op.InvalidateOp{}.Add(gtx.Ops)
+	}
+	return dims
+}
+
+// layoutHeading draws the header above the list, so that it does not scroll away.
+// It follows the horizontal scrolling of the list.
+func (t *TableDef) layoutHeading(gtx C) D {
+	macro := op.Record(gtx.Ops)
+	dims := t.heading(gtx)
+	call := macro.Stop()
+	dims.Size.X = gtx.Constraints.Max.X
+	defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+	defer op.Offset(f32.Pt(float32(-t.list.Hpos), 0)).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+	return dims
+}
+
+// layoutWidths calculates the widths of the cells, from the column weights or the sizes set by the user.
+func (t *TableDef) layoutWidths(gtx C) {
+	weights := t.weights()
+	if len(t.widths) != len(weights) {
+		t.widths = make([]int, len(weights))
+		t.handles = make([]colHandle, len(weights))
+	}
+	if t.list.AnchorStrategy == Occupy {
+		gtx.Constraints.Max.X -= gtx.Px(t.list.VScrollBar.Width(gtx.Metric))
+	}
+	if t.sized != nil {
+		// Columns that have not been sized get their fixed width, or their part of the table width.
+		for i, w := range t.sized {
+			if w > 0 || i >= len(weights) {
+				continue
+			}
+			if weights[i] > 1 {
+				t.sized[i] = gtx.Px(t.th.TextSize.Scale(weights[i])) / 2
+			} else {
+				t.sized[i] = int(weights[i] * float32(gtx.Constraints.Max.X))
+			}
+		}
+		copy(t.widths, t.sized)
+		return
+	}
+	calcWidths(gtx, t.th.TextSize, weights, t.widths)
+}
+
+// firstCol returns the index in widths of the first column, which is after the checkbox column if there is one.
+func (t *TableDef) firstCol() int {
+	if t.selField != nil {
+		return 1
+	}
+	return 0
+}
+
+// frozenCells returns the number of cells in a row that do not scroll horizontally.
+func (t *TableDef) frozenCells() int {
+	if t.frozen == 0 {
+		return 0
+	}
+	return t.firstCol() + t.frozen
+}
+
+// tableRow returns a row of cells with the widths of the columns, where the frozen columns
+// stay in place when the table is scrolled horizontally. If r is nil, the row can not be selected.
+func (t *TableDef) tableRow(th *Theme, r *rowDef, cells ...layout.Widget) layout.Widget {
+	if r == nil {
+		r = &rowDef{selected: func() bool { return false }, onClick: func(c Click) {}}
+	}
+	r.frozen = t.frozenCells()
+	r.hpos = func() int { return t.list.Hpos }
+	// The widths of the cells are shared by the header and all rows.
+	return r.layout(th, func(C) []int { return t.widths }, cells...)
+}
+
+// resizing handles the drags and double clicks on the borders between the header cells.
+// The first resize changes all columns to fixed widths, so that the other columns keep their size.
+func (t *TableDef) resizing(gtx C) {
+	for i := t.firstCol(); i < len(t.handles); i++ {
+		h := &t.handles[i]
+		for _, e := range h.click.Events(gtx) {
+			if e.Type == gesture.TypeClick && e.NumClicks == 2 {
+				t.fixWidths()
+				t.sized[i] = t.fitWidth(gtx, i-t.firstCol())
+			}
+		}
+		dragged := false
+		var dx float32
+		for _, e := range h.drag.Events(gtx.Metric, gtx, gesture.Horizontal) {
+			if e.Type == pointer.Press {
+				t.dragStart = e.Position.X
+			} else if e.Type == pointer.Drag {
+				// The handle is moved by each change, so only the last position counts.
+				dx = e.Position.X - t.dragStart
+				dragged = true
+			}
+		}
+		if dragged {
+			t.fixWidths()
+			t.sized[i] += int(dx)
+			if min := t.minWidth(gtx, i-t.firstCol()); t.sized[i] < min {
+				t.sized[i] = min
+			}
+		}
+	}
+	if t.sized != nil {
+		copy(t.widths, t.sized)
+	}
+}
+
+// fixWidths changes to the fixed widths set by the user, starting with the current widths.
+func (t *TableDef) fixWidths() {
+	if t.sized == nil {
+		t.sized = append([]int(nil), t.widths...)
+	}
+}
+
+// minWidth returns the minimum width of column col in pixels.
+func (t *TableDef) minWidth(gtx C, col int) int {
+	w := t.columns[col].MinWidth
+	if w == 0 {
+		w = 4
+	}
+	return gtx.Px(t.th.TextSize.Scale(w)) / 2
+}
+
+// fitWidth returns the width in pixels needed by column col, measured on the header and the visible rows.
+func (t *TableDef) fitWidth(gtx C, col int) int {
+	// Leave room for the button padding and the sort icon in the header.
+	w := textWidth(gtx, &t.thh, t.columns[col].Title) + gtx.Px(t.th.TextSize.Scale(4))
+	pos := t.list.list.Position
+	for i := pos.First; i < pos.First+pos.Count && i < t.listLen(); i++ {
+		r := t.viewRow(i)
+		if r < 0 {
+			continue
+		}
+		if tw := textWidth(gtx, &t.thg, t.text(t.elem(t.view[r]), col)); tw > w {
+			w = tw
+		}
+	}
+	if min := t.minWidth(gtx, col); w < min {
+		w = min
+	}
+	return w
+}
+
+// textWidth returns the width of a label with the text s, without drawing it.
+func textWidth(gtx C, th *Theme, s string) int {
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	gtx.Constraints.Max.X = inf
+	dims := th.LabelPadding.Layout(gtx, func(gtx C) D {
+		return aLabel{MaxLines: 1}.Layout(gtx, th.Shaper, text.Font{Weight: text.Medium}, th.TextSize, s)
+	})
+	macro.Stop()
+	return dims.Size.X
+}
+
+// layoutHandles adds the drag handles at the right side of each header cell.
+func (t *TableDef) layoutHandles(gtx C, height int) {
+	half := gtx.Px(t.th.TextSize.Scale(0.25))
+	frozen := t.frozenCells()
+	right := 0
+	for _, w := range t.widths[:clampInt(frozen, 0, len(t.widths))] {
+		right += w
+	}
+	x := 0
+	for i, w := range t.widths {
+		x += w
+		if i < t.firstCol() {
+			continue
+		}
+		hx := x
+		if i < frozen {
+			hx += t.list.Hpos
+		} else if x-t.list.Hpos <= right {
+			// The border is scrolled under the frozen columns.
+			continue
+		}
+		st := clip.Rect(image.Rect(hx-half, 0, hx+half, height)).Push(gtx.Ops)
+		t.handles[i].drag.Add(gtx.Ops)
+		t.handles[i].click.Add(gtx.Ops)
+		pointer.CursorNameOp{Name: pointer.CursorColResize}.Add(gtx.Ops)
+		st.Pop()
+	}
+}
+
+// weights returns the column widths as weights for Row, with the checkbox column first if rows can be selected.
+func (t *TableDef) weights() []float32 {
+	var w []float32
+	if t.selField != nil {
+		w = append(w, 4)
+	}
+	for _, c := range t.columns {
+		w = append(w, c.Width)
+	}
+	return w
+}
+
+// makeHeading returns the header row, with buttons that sort on the columns with fields.
+func (t *TableDef) makeHeading(th *Theme) layout.Widget {
+	var cells []layout.Widget
+	if t.selField != nil {
+		cells = append(cells, TriCheckbox(&t.thh, "", &t.selectAll, t.onSelectAll))
+	}
+	t.header = make([]*ButtonDef, len(t.columns))
+	for i, c := range t.columns {
+		if t.fields[i] == nil || t.pager != nil {
+			cells = append(cells, Label(&t.thh, c.Title, Bold()))
+			continue
+		}
+		col := i
+		t.header[i] = newButton(Text, &t.thh, c.Title, AlignLeft(), W(9999), Handler(func() { t.sortBy(col) }))
+		cells = append(cells, t.header[i].Layout)
+	}
+	heading := t.tableRow(&t.thh, nil, cells...)
+	t.menu = t.makeMenu()
+	widgets := []layout.Widget{
+		func(gtx C) D {
+			dims := heading(gtx)
+			t.layoutMovers(gtx, dims.Size.Y)
+			t.layoutHandles(gtx, dims.Size.Y)
+			t.layoutMenu(gtx, dims.Size)
+			return dims
+		},
+	}
+	if filters := t.makeFilters(); filters != nil {
+		widgets = append(widgets, filters)
+	}
+	return Col(append(widgets, Separator(th, unit.Dp(2.0), W(9999)))...)
+}
+
+// lineCount returns the number of lines in the list, for all pages.
+func (t *TableDef) lineCount() int {
+	if t.grouped() {
+		return len(t.lines)
+	}
+	return len(t.view)
+}
+
+// listLen returns the number of lines in the list, which is the lines on the current page if the table has pages.
+func (t *TableDef) listLen() int {
+	if t.page == nil {
+		return t.lineCount()
+	}
+	return clampInt(t.lineCount()-t.pageStart(), 0, *t.pageSize)
+}
+
+// viewRow returns the row in the view shown at line i in the list, or -1 for group headers and footers.
+func (t *TableDef) viewRow(i int) int {
+	i += t.pageStart()
+	if !t.grouped() {
+		return i
+	}
+	if t.lines[i].kind != dataLine {
+		return -1
+	}
+	return t.lines[i].index
+}
+
+// listIndex returns the line in the list where row r in the view is shown. It is outside the list
+// if the row is on another page.
+func (t *TableDef) listIndex(r int) int {
+	if !t.grouped() {
+		return r - t.pageStart()
+	}
+	for i, l := range t.lines {
+		if l.kind == dataLine && l.index == r {
+			return i - t.pageStart()
+		}
+	}
+	return 0
+}
+
+// rowKey returns the key of line i of the list, which is the index of the row in the data, so that
+// the widgets of a row keep their state when the rows are sorted or filtered.
+func (t *TableDef) rowKey(i int) string {
+	i += t.pageStart()
+	if t.grouped() {
+		switch l := t.lines[i]; l.kind {
+		case groupLine:
+			return "group" + strconv.Itoa(l.index)
+		case footerLine:
+			return "footer" + strconv.Itoa(l.index)
+		default:
+			i = l.index
+		}
+	}
+	id := t.view[i]
+	if t.pager != nil && !t.pager.loaded(id) {
+		return "loading" + strconv.Itoa(id)
+	}
+	return strconv.Itoa(id)
+}
+
+// row returns the widget for line i of the list.
+func (t *TableDef) row(i int) layout.Widget {
+	i += t.pageStart()
+	if t.grouped() {
+		switch l := t.lines[i]; l.kind {
+		case groupLine:
+			return t.groupHeader(l.index)
+		case footerLine:
+			return t.footer(l.index)
+		default:
+			i = l.index
+		}
+	}
+	id := t.view[i]
+	if t.pager != nil && !t.pager.loaded(id) {
+		t.pager.want(id / pageSize)
+		return t.placeholder(i)
+	}
+	e := t.elem(id)
+	var cells []layout.Widget
+	if t.selField != nil {
+		selected := e.FieldByIndex(t.selField).Addr().Convert(reflect.TypeOf((*bool)(nil))).Interface().(*bool)
+		cells = append(cells, Checkbox(&t.thg, "", selected, func(b bool) { t.sel.update(id, b) }))
+	}
+	for col, c := range t.columns {
+		if c.Cell != nil {
+			w := c.Cell(&t.thg, e.Addr().Interface())
+			if t.nodes != nil && col == 0 {
+				w = t.expander(id, w)
+			}
+			cells = append(cells, t.cell(i, col, w))
+			continue
+		}
+		col := col
+		w := Value(&t.thg, func() string { return t.text(e, col) })
+		if t.nodes != nil && col == 0 {
+			w = t.expander(id, w)
+		}
+		cells = append(cells, t.cell(i, col, w))
+	}
+	r := &rowDef{
+		selected: func() bool { return t.sel.Selected(id) },
+		onClick: func(c Click) {
+			if c.NumClicks == 1 {
+				t.sel.Click(id, c.Modifiers, t.view)
+			}
+		},
+	}
+	return Col(
+		t.tableRow(&t.thg, r, cells...),
+		Separator(t.th, unit.Dp(0.5), W(9999)),
+	)
+}
+
+// elem returns the struct of row i, which is element i in the data, or node i in a tree table.
+func (t *TableDef) elem(i int) reflect.Value {
+	if t.nodes != nil {
+		return t.nodes[i].v
+	}
+	if t.pager != nil {
+		return t.pager.elem(i)
+	}
+	return t.dataElem(i)
+}
+
+// dataElem returns the struct of element i in the data.
+func (t *TableDef) dataElem(i int) reflect.Value {
+	e := t.data.Index(i)
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+	return e
+}
+
+// text returns the formatted field of column col in the struct e.
+func (t *TableDef) text(e reflect.Value, col int) string {
+	if t.fields[col] == nil {
+		return ""
+	}
+	v := e.FieldByIndex(t.fields[col])
+	if t.columns[col].Format != nil {
+		return t.columns[col].Format(v.Interface())
+	}
+	if o := t.columns[col].Options; len(o) > 0 && isInteger(v.Kind()) {
+		if n := intValue(v); n >= 0 && n < int64(len(o)) {
+			return o[n]
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// intValue returns the value of an integer of any kind. Unsigned values too large for an int64 are negative.
+func intValue(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+// sortBy sorts on column col, or reverses the order if the table is already sorted on it.
+func (t *TableDef) sortBy(col int) {
+	if t.sortCol == col {
+		t.sortUp = !t.sortUp
+	} else {
+		t.sortCol = col
+		t.sortUp = true
+	}
+	t.sort()
+}
+
+// sort orders the view on the sort column. A tree table is sorted within the children of each row,
+// and grouped rows within each group.
+func (t *TableDef) sort() {
+	switch {
+	case t.nodes != nil:
+		// The rows of a tree table that are found change when a row is expanded or collapsed.
+		t.view = t.flatten()
+		t.selChanged = t.selField != nil
+	case t.grouped():
+		for _, g := range t.groups {
+			t.sortRows(g.rows)
+		}
+		t.arrange()
+	default:
+		t.sortRows(t.view)
+	}
+	t.list.reset()
+}
+
+// sortRows orders the rows on the sort column.
+func (t *TableDef) sortRows(rows []int) {
+	if t.sortCol >= 0 && t.sortCol < len(t.fields) && t.fields[t.sortCol] != nil {
+		f := t.fields[t.sortCol]
+		sort.SliceStable(rows, func(i, j int) bool {
+			a := t.elem(rows[i]).FieldByIndex(f)
+			b := t.elem(rows[j]).FieldByIndex(f)
+			if t.sortUp {
+				return less(a, b)
+			}
+			return less(b, a)
+		})
+	}
+}
+
+// less compares two values of the same kind.
+func less(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.String:
+		return a.String() < b.String()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// onSelectAll is called when the header checkbox is clicked, and will select or clear all rows.
+func (t *TableDef) onSelectAll(s CheckState) {
+	for _, i := range t.foundRows() {
+		t.sel.Set(i, s == Checked)
+	}
+	t.sel.notify()
+}
+
+// selectState returns the state of the header checkbox, which is checked when all rows found by the
+// filters are selected, and indeterminate when some of them are.
+func (t *TableDef) selectState() CheckState {
+	if t.sel.Len() == 0 {
+		return Unchecked
+	}
+	n, rows := 0, t.foundRows()
+	for _, i := range rows {
+		if t.sel.Selected(i) {
+			n++
+		}
+	}
+	switch {
+	case n == 0:
+		return Unchecked
+	case n == len(rows):
+		return Checked
+	}
+	return Indeterminate
+}
+
+// cell returns the widget for the cell in row r and column col, which shows the cell cursor and the
+// editor, and handles clicks on the cell.
+func (t *TableDef) cell(r, col int, w layout.Widget) layout.Widget {
+	var click gesture.Click
+	return func(gtx C) D {
+		at := t.curRow == r && t.curCol == col
+		if at && t.editor != nil {
+			return t.editor.Layout(gtx)
+		}
+		for _, e := range click.Events(gtx) {
+			if e.Type != gesture.TypeClick {
+				continue
+			}
+			t.curRow, t.curCol = r, col
+			// Shift-click marks the cells from the last cell clicked without Shift.
+			t.block = e.Modifiers.Contain(key.ModShift)
+			if !t.block {
+				t.blockRow, t.blockCol = r, col
+			}
+			t.Focus()
+			if e.NumClicks == 2 {
+				t.startEdit()
+			}
+		}
+		dims := w(gtx)
+		// The row below gets the clicks too, so that they also select the row.
+		defer pointer.PassOp{}.Push(gtx.Ops).Pop()
+		defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+		click.Add(gtx.Ops)
+		if t.inBlock(r, col) {
+			paint.FillShape(gtx.Ops, MulAlpha(t.th.Primary, 40), clip.Rect{Max: dims.Size}.Op())
+		}
+		if at && t.Focused() {
+			paintBorder(gtx, f32.Rectangle{Max: layout.FPt(dims.Size)}, t.th.Primary, t.th.BorderThicknessActive, unit.Dp(0))
+		}
+		return dims
+	}
+}
+
+// handleKeys moves the cell cursor with the arrow keys, toggles the selection of the row with Space,
+// starts editing with Enter or F2, copies and pastes with Ctrl-C and Ctrl-V, and exports with Ctrl-E.
+func (t *TableDef) handleKeys(gtx C) {
+	for _, ev := range gtx.Events(&t.eventKey) {
+		switch ke := ev.(type) {
+		case key.FocusEvent:
+			t.focused = ke.Focus
+		// Complete a paste started with Ctrl-V.
+		case clipboard.Event:
+			t.paste(ke.Text)
+		case key.Event:
+			if !t.focused || ke.State != key.Press {
+				break
+			}
+			switch ke.Name {
+			case key.NameUpArrow, key.NameDownArrow, key.NameLeftArrow, key.NameRightArrow:
+				t.arrowKey(ke)
+			case key.NameSpace:
+				if t.curRow < len(t.view) {
+					t.sel.Click(t.view[t.curRow], key.ModShortcut, t.view)
+				}
+			case key.NameReturn, key.NameEnter, key.NameF2:
+				t.startEdit()
+			case key.NameEscape:
+				t.block = false
+				t.menuOpen = false
+			case "C":
+				if ke.Modifiers.Contain(key.ModShortcut) {
+					clipboard.WriteOp{Text: t.copyText()}.Add(gtx.Ops)
+				}
+			case "V":
+				if ke.Modifiers.Contain(key.ModShortcut) {
+					clipboard.ReadOp{Tag: &t.eventKey}.Add(gtx.Ops)
+				}
+			case "E":
+				if ke.Modifiers.Contain(key.ModShortcut) {
+					t.exportFile()
+				}
+			case key.NameTab:
+				if !ke.Modifiers.Contain(key.ModShift) {
+					if t.Next() != nil {
+						t.Next().Focus()
+					}
+				} else if t.Prev() != nil {
+					t.Prev().Focus()
+				}
+			}
+		}
+	}
+	key.InputOp{Tag: &t.eventKey}.Add(gtx.Ops)
+	if t.requestFocus {
+		key.FocusOp{Tag: &t.eventKey}.Add(gtx.Ops)
+		key.SoftKeyboardOp{Show: false}.Add(gtx.Ops)
+	}
+	t.requestFocus = false
+}
+
+// arrowKey moves the cell cursor. With Shift, up and down extends the row selection from the anchor
+// to the cursor, and left and right marks the block of cells from the block anchor to the cursor.
+// In a tree table, left and right collapses and expands rows, see treeKey.
+func (t *TableDef) arrowKey(ke key.Event) {
+	if !ke.Modifiers.Contain(key.ModShift) && t.treeKey(ke.Name) {
+		return
+	}
+	switch ke.Name {
+	case key.NameUpArrow:
+		t.moveCursor(-1, 0)
+	case key.NameDownArrow:
+		t.moveCursor(1, 0)
+	case key.NameLeftArrow:
+		t.moveCursor(0, -1)
+	case key.NameRightArrow:
+		t.moveCursor(0, 1)
+	}
+	if !ke.Modifiers.Contain(key.ModShift) {
+		t.block = false
+		t.blockRow, t.blockCol = t.curRow, t.curCol
+		return
+	}
+	switch ke.Name {
+	case key.NameUpArrow, key.NameDownArrow:
+		if t.curRow < len(t.view) {
+			t.sel.Extend(t.view[t.curRow], t.view)
+		}
+	default:
+		t.block = true
+	}
+}
+
+// moveCursor moves the cell cursor dr rows and dc columns, and scrolls the row into view.
+func (t *TableDef) moveCursor(dr, dc int) {
+	t.curRow = clampInt(t.curRow+dr, 0, len(t.view)-1)
+	t.curCol = clampInt(t.curCol+dc, 0, len(t.columns)-1)
+	i := t.listIndex(t.curRow)
+	if t.page != nil && (i < 0 || i >= *t.pageSize) {
+		// Show the page with the cursor.
+		*t.page = (i + t.pageStart()) / *t.pageSize
+		t.showPage()
+		i = t.listIndex(t.curRow)
+	}
+	t.list.scrollTo(i)
+}
+
+// startEdit starts editing the cell at the cursor, if the column is editable.
+func (t *TableDef) startEdit() {
+	if t.curRow >= len(t.view) || t.curCol >= len(t.columns) {
+		return
+	}
+	t.editID = t.view[t.curRow]
+	t.editValue = t.cellBinding(t.elem(t.editID), t.curCol)
+	if b, ok := t.editValue.(boolBinding); ok {
+		b.v.SetBool(!b.v.Bool())
+		t.Changed(t.editID)
+		return
+	}
+	if t.editValue == nil {
+		return
+	}
+	t.th.form.detach(func() { t.editor = newEdit(&t.thg) })
+	t.editor.padding = layout.Inset{}
+	t.editor.onKey = t.editKey
+	t.editor.SetText(t.editValue.format())
+	t.editor.SetCaret(t.editor.Len(), 0)
+	t.editor.Focus()
+	t.editFocused = false
+}
+
+// editKey handles the keys that end editing. Enter and Tab will write the text to the field,
+// and Escape will drop the changes.
+func (t *TableDef) editKey(k key.Event) bool {
+	switch k.Name {
+	case key.NameReturn, key.NameEnter:
+		t.commitEdit()
+	case key.NameTab:
+		if !t.commitEdit() {
+			break
+		}
+		if k.Modifiers.Contain(key.ModShift) {
+			t.moveCursor(0, -1)
+		} else {
+			t.moveCursor(0, 1)
+		}
+	case key.NameEscape:
+		t.editor = nil
+		t.Focus()
+	default:
+		return false
+	}
+	return true
+}
+
+// commitEdit writes the edited text to the field. If it is not valid, the error is shown and editing goes on.
+func (t *TableDef) commitEdit() bool {
+	if err := t.editValue.parse(t.editor.Text()); err != nil {
+		t.editor.err = err
+		return false
+	}
+	t.Changed(t.editID)
+	t.editor = nil
+	t.Focus()
+	return true
+}
+
+func clampInt(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
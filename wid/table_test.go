@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"gioui.org/layout"
+)
+
+type person struct {
+	Name   string
+	Age    int
+	hidden string
+}
+
+func TestFieldIndex(t *testing.T) {
+	typ := reflect.TypeOf(person{})
+	if i := fieldIndex(typ, "Age"); !reflect.DeepEqual(i, []int{1}) {
+		t.Errorf("fieldIndex(Age) = %v, want [1]", i)
+	}
+	if i := fieldIndex(typ, ""); i != nil {
+		t.Errorf("fieldIndex(\"\") = %v, want nil", i)
+	}
+	for _, name := range []string{"hidden", "Missing"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("fieldIndex(%s) did not panic", name)
+				}
+			}()
+			fieldIndex(typ, name)
+		}()
+	}
+}
+
+func TestFilterBinding(t *testing.T) {
+	var table TableDef
+	var s string
+	e := &EditDef{}
+	table.bindFilter(&s)(e)
+	if err := e.value.parse(""); err != nil || table.dirty {
+		t.Fatalf("parse of the same text: err %v, dirty %v", err, table.dirty)
+	}
+	if err := e.value.parse("abc"); err != nil || !table.dirty || s != "abc" {
+		t.Errorf("parse of a new text: err %v, dirty %v, text %q", err, table.dirty, s)
+	}
+}
+
+func TestRestoredWidths(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	data := []person{{Name: "Ann", Age: 30}}
+	columns := []Column{{Title: "Name", Field: "Name", Width: 0.5}, {Title: "Age", Field: "Age", Width: 0.5}}
+	table := Table(th, Overlay, &data, columns)
+	// The layout is restored before the table has been drawn, so its width is not known yet.
+	table.unit = 7
+	table.SetColumnLayout(ColumnLayout{Columns: []ColumnState{{Name: "Age", Width: 10}, {Name: "Name"}}})
+	for _, f := range table.pending {
+		f()
+	}
+	gtx := layout.Context{Constraints: layout.Constraints{Max: image.Pt(1000, 500)}}
+	table.layoutWidths(gtx)
+	if want := []int{70, 500}; !reflect.DeepEqual(table.widths, want) {
+		t.Errorf("widths are %v, want %v", table.widths, want)
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	type row struct {
+		Selected bool
+		Name     string
+		Kind     string
+	}
+	data := []row{{false, "Apple", "Tree"}, {false, "Banana", "Herb"}, {false, "Cherry", "Tree"}}
+	table := Table(th, Occupy, &data, []Column{{Title: "Name", Field: "Name"}}, SelectedField("Selected"), GroupBy("Kind"))
+	table.selChanged = false
+	table.collapsed[table.groups[1].label] = true
+	table.sort()
+	table.onSelectAll(Checked)
+	if !table.selChanged {
+		t.Errorf("selecting all rows did not mark the header checkbox")
+	}
+	for i, r := range data {
+		if !r.Selected {
+			t.Errorf("row %d of a collapsed group is not selected", i)
+		}
+	}
+	if s := table.selectState(); s != Checked {
+		t.Errorf("the header checkbox is %v with all rows selected", s)
+	}
+	table.selChanged = false
+	table.Selection().Set(0, false)
+	if s := table.selectState(); !table.selChanged || s != Indeterminate {
+		t.Errorf("the header checkbox is %v after a row is deselected", s)
+	}
+}
+
+func TestOptionText(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	type row struct {
+		A int
+		B int8
+		C uint16
+	}
+	data := []row{{1, 2, 0}, {3, -1, 9}}
+	names := []string{"Zero", "One", "Two"}
+	table := Table(th, Occupy, &data, []Column{{Field: "A", Options: names}, {Field: "B", Options: names}, {Field: "C", Options: names}})
+	for i, want := range [][]string{{"One", "Two", "Zero"}, {"3", "-1", "9"}} {
+		for col := range want {
+			if got := table.text(table.dataElem(i), col); got != want[col] {
+				t.Errorf("row %d column %d is %q, want %q", i, col, got, want[col])
+			}
+		}
+	}
+}
+
+func TestOptionFilter(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	type row struct {
+		Size uint8
+	}
+	data := []row{{0}, {1}, {1}}
+	table := Table(th, Occupy, &data, []Column{{Field: "Size", Options: []string{"Small", "Large"}, Filter: true}})
+	table.filters[0].option = 2
+	table.Refresh()
+	if visible, _ := table.Count(); visible != 2 {
+		t.Errorf("the filter on Large found %d rows, want 2", visible)
+	}
+}
@@ -33,6 +33,7 @@ type Theme struct {
 	DefaultFont           text.Font
 	CheckBoxChecked       *Icon
 	CheckBoxUnchecked     *Icon
+	CheckBoxIndeterminate *Icon
 	RadioChecked          *Icon
 	RadioUnchecked        *Icon
 	FingerSize            unit.Value // FingerSize is the minimum touch target size.
@@ -63,6 +64,16 @@ type Theme struct {
 	SashColor  color.NRGBA
 	SashWidth  unit.Value
 	TrackColor color.NRGBA
+	// form is the form that widgets made with this theme belong to, set by NewForm or Form.SetTheme.
+	// A copy of the theme belongs to the same form, so that widgets can be styled with a modified copy.
+	form *Form
+}
+
+// Form returns the form that widgets made with the theme belong to, or nil if the theme is not
+// given to a form. Widgets made with a theme without a form work, but are not in a tab sequence,
+// and keyed widgets do not keep their state when they are made again.
+func (th *Theme) Form() *Form {
+	return th.form
 }
 
 type (
@@ -107,6 +118,7 @@ func NewTheme(fontCollection []text.FontFace, fontSize float32, p Palette) *Them
 	// Icons
 	t.CheckBoxChecked = mustIcon(NewIcon(icons.ToggleCheckBox))
 	t.CheckBoxUnchecked = mustIcon(NewIcon(icons.ToggleCheckBoxOutlineBlank))
+	t.CheckBoxIndeterminate = mustIcon(NewIcon(icons.ToggleIndeterminateCheckBox))
 	t.RadioChecked = mustIcon(NewIcon(icons.ToggleRadioButtonChecked))
 	t.RadioUnchecked = mustIcon(NewIcon(icons.ToggleRadioButtonUnchecked))
 	t.IconInset = layout.Inset{Top: v, Right: v, Bottom: v, Left: v}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"image"
+	"reflect"
+
+	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+)
+
+// treeNode is a row of a tree table. The first nodes are the elements of the data, in the same order,
+// and the children follow in the order they are loaded.
+type treeNode struct {
+	v reflect.Value
+	// parent is the node this is a child of, -1 for the elements of the data.
+	parent int
+	level  int
+	// children is the nodes of the children, loaded the first time the node is expanded.
+	children []int
+	loaded   bool
+	expanded bool
+}
+
+// TreeChildren is an option parameter that makes the table a tree, where the rows can be expanded to show
+// their children. children returns the children of the element p, which is a pointer to the struct, as a slice
+// of the same type as the data. It is called the first time the row is expanded. hasChildren returns true if
+// p has children, and decides if the row gets an expander before it is loaded. It can be nil, and then all
+// rows get one. The first column is indented by the level in the tree, and the expander is drawn in it.
+// Children are sorted among their siblings, and a row is shown if it, or a loaded child, is found by the filters.
+// In a Selection, children are given by numbers following the elements of the data.
+func TreeChildren(children func(p interface{}) interface{}, hasChildren func(p interface{}) bool) TableOption {
+	return func(t *TableDef) {
+		t.children = children
+		t.hasChildren = hasChildren
+		t.nodes = []treeNode{}
+	}
+}
+
+// makeNodes makes the nodes for the n elements of the data. The loaded children are kept if the
+// number of elements is the same as before.
+func (t *TableDef) makeNodes(n int) {
+	roots := 0
+	for roots < len(t.nodes) && t.nodes[roots].parent < 0 {
+		roots++
+	}
+	if roots != n {
+		t.nodes = make([]treeNode, n)
+		for i := range t.nodes {
+			t.nodes[i].parent = -1
+		}
+	}
+	for i := 0; i < n; i++ {
+		t.nodes[i].v = t.dataElem(i)
+	}
+}
+
+// load adds the children of node id.
+func (t *TableDef) load(id int) {
+	t.nodes[id].loaded = true
+	if t.children == nil {
+		return
+	}
+	s := reflect.ValueOf(t.children(t.nodes[id].v.Addr().Interface()))
+	if s.Kind() == reflect.Ptr {
+		s = s.Elem()
+	}
+	if s.Kind() != reflect.Slice {
+		return
+	}
+	for j := 0; j < s.Len(); j++ {
+		e := s.Index(j)
+		if e.Kind() == reflect.Ptr {
+			e = e.Elem()
+		}
+		t.nodes = append(t.nodes, treeNode{v: e, parent: id, level: t.nodes[id].level + 1})
+		t.nodes[id].children = append(t.nodes[id].children, len(t.nodes)-1)
+	}
+}
+
+// canExpand returns true if node id has children, or may have children when it is loaded.
+func (t *TableDef) canExpand(id int) bool {
+	n := &t.nodes[id]
+	if n.loaded {
+		return len(n.children) > 0
+	}
+	return t.hasChildren == nil || t.hasChildren(n.v.Addr().Interface())
+}
+
+// expand expands or collapses node id, and keeps the cursor on the row it was on.
+func (t *TableDef) expand(id int, expanded bool) {
+	if expanded && !t.nodes[id].loaded {
+		t.load(id)
+	}
+	t.nodes[id].expanded = expanded
+	cur := -1
+	if t.curRow < len(t.view) {
+		cur = t.view[t.curRow]
+	}
+	t.sort()
+	t.curRow = clampInt(t.curRow, 0, len(t.view)-1)
+	for r, v := range t.view {
+		if v == cur {
+			t.curRow = r
+		}
+	}
+}
+
+// flatten returns the nodes shown, with the children of expanded nodes after their parent.
+func (t *TableDef) flatten() []int {
+	var view []int
+	var add func(ids []int)
+	add = func(ids []int) {
+		ids = append([]int(nil), ids...)
+		t.sortRows(ids)
+		for _, id := range ids {
+			if !t.found(id) {
+				continue
+			}
+			view = append(view, id)
+			if t.nodes[id].expanded {
+				add(t.nodes[id].children)
+			}
+		}
+	}
+	roots := make([]int, t.total)
+	for i := range roots {
+		roots[i] = i
+	}
+	add(roots)
+	return view
+}
+
+// found returns true if node id or one of its loaded children is found by the filters.
+func (t *TableDef) found(id int) bool {
+	if t.match(t.nodes[id].v) {
+		return true
+	}
+	for _, c := range t.nodes[id].children {
+		if t.found(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// expander returns the widget w for the first column of node id, indented by the level and with an
+// icon that expands or collapses the node.
+func (t *TableDef) expander(id int, w layout.Widget) layout.Widget {
+	var click gesture.Click
+	return func(gtx C) D {
+		for _, e := range click.Events(gtx) {
+			if e.Type == gesture.TypeClick {
+				t.later(func() { t.expand(id, !t.nodes[id].expanded) })
+			}
+		}
+		size := gtx.Px(t.th.TextSize.Scale(1.2))
+		indent := t.nodes[id].level * size
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				dims := D{Size: image.Pt(indent+size, size)}
+				if !t.canExpand(id) {
+					return dims
+				}
+				icon := t.expandIcon
+				if t.nodes[id].expanded {
+					icon = t.collapseIcon
+				}
+				defer op.Offset(f32.Pt(float32(indent), 0)).Push(gtx.Ops).Pop()
+				gtx.Constraints = layout.Exact(image.Pt(size, size))
+				icon.Layout(gtx, t.thg.OnBackground)
+				defer clip.Rect{Max: image.Pt(size, size)}.Push(gtx.Ops).Pop()
+				click.Add(gtx.Ops)
+				return dims
+			}),
+			layout.Flexed(1, w),
+		)
+	}
+}
+
+// treeKey expands the row at the cursor with Right and collapses it with Left, when the cursor is in
+// the first column. Left on a row that is not expanded moves the cursor to the parent.
+// It returns false if the key is not used.
+func (t *TableDef) treeKey(name string) bool {
+	if t.nodes == nil || t.curCol != 0 || t.curRow >= len(t.view) {
+		return false
+	}
+	id := t.view[t.curRow]
+	n := t.nodes[id]
+	switch {
+	case name == key.NameRightArrow && !n.expanded && t.canExpand(id):
+		t.expand(id, true)
+	case name == key.NameLeftArrow && n.expanded:
+		t.expand(id, false)
+	case name == key.NameLeftArrow && n.parent >= 0:
+		for r, v := range t.view {
+			if v == n.parent {
+				t.moveCursor(r-t.curRow, 0)
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
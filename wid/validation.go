@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validator is a field that can check its value, like an edit with validation options.
+type validator interface {
+	Focuser
+	// validate checks the value of the field, and shows the error (if any) under the field.
+	validate() error
+	// name returns the name used in error messages from the form.
+	name() string
+}
+
+// Required is an option parameter that makes the edit invalid when it is empty.
+func Required() EditOption {
+	return func(e *EditDef) {
+		e.validators = append(e.validators, func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return errors.New("a value is required")
+			}
+			return nil
+		})
+	}
+}
+
+// Range is an option parameter that makes the edit invalid when the text is not a number
+// between min and max. An empty text is accepted, use Required() to reject it.
+func Range(min, max float64) EditOption {
+	return func(e *EditDef) {
+		e.validators = append(e.validators, func(s string) error {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				return nil
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+			if f < min || f > max {
+				return fmt.Errorf("must be between %v and %v", min, max)
+			}
+			return nil
+		})
+	}
+}
+
+// Regexp is an option parameter that makes the edit invalid when the text does not match the
+// regular expression expr. An empty text is accepted, use Required() to reject it.
+// It panics if expr can not be compiled.
+func Regexp(expr string) EditOption {
+	re := regexp.MustCompile(expr)
+	return func(e *EditDef) {
+		e.validators = append(e.validators, func(s string) error {
+			if s == "" || re.MatchString(s) {
+				return nil
+			}
+			return errors.New("invalid format")
+		})
+	}
+}
+
+// Check is an option parameter that makes the edit invalid when f returns an error.
+// The text of the error is shown under the edit.
+func Check(f func(s string) error) EditOption {
+	return func(e *EditDef) {
+		e.validators = append(e.validators, f)
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package wid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []EditOption
+		text    string
+		err     string
+	}{
+		{"required empty", []EditOption{Required()}, "  ", "a value is required"},
+		{"required", []EditOption{Required()}, "x", ""},
+		{"range empty", []EditOption{Range(1, 10)}, "", ""},
+		{"range", []EditOption{Range(1, 10)}, " 10 ", ""},
+		{"range above", []EditOption{Range(1, 10)}, "11", "must be between 1 and 10"},
+		{"range text", []EditOption{Range(1, 10)}, "ten", "must be a number"},
+		{"required range", []EditOption{Required(), Range(1, 10)}, "", "a value is required"},
+		{"regexp", []EditOption{Regexp(`^[a-z]+@[a-z]+$`)}, "a@b", ""},
+		{"regexp mismatch", []EditOption{Regexp(`^[a-z]+@[a-z]+$`)}, "a@", "invalid format"},
+		{"check", []EditOption{Check(func(s string) error { return errors.New("no " + s) })}, "way", "no way"},
+	}
+	for _, test := range tests {
+		e := &EditDef{}
+		for _, o := range test.options {
+			o(e)
+		}
+		err := e.check(test.text)
+		if got := errString(err); got != test.err {
+			t.Errorf("%s: check(%q) = %q, want %q", test.name, test.text, got, test.err)
+		}
+	}
+}
+
+func TestCheckParses(t *testing.T) {
+	n := 3
+	e := &EditDef{}
+	BindInt(&n)(e)
+	Range(0, 100)(e)
+	if err := e.check("42"); err != nil || n != 42 {
+		t.Errorf("check(\"42\") = %v, n = %d, want nil and 42", err, n)
+	}
+	if err := e.check("420"); err == nil || n != 42 {
+		t.Errorf("check(\"420\") = %v, n = %d, want an error and 42", err, n)
+	}
+}
+
+func TestValidateSkipsDetached(t *testing.T) {
+	th := NewTheme(nil, 14, Palette{})
+	f := NewForm(th)
+	f.Init()
+	newEdit(th, Lbl("Name"), Required())
+	f.detach(func() { newEdit(th, Lbl("Filter"), Required()) })
+	errs := f.Validate()
+	if len(errs) != 1 || errs[0].Error() != "Name: a value is required" {
+		t.Errorf("Validate() = %v, want only the error of Name", errs)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -17,6 +17,7 @@ type Widget struct {
 	padding layout.Inset
 	width   unit.Value
 	fgColor color.NRGBA
+	key     string
 }
 
 // WidgetIf is the interface functions for widgets, used by options to set parameters
@@ -47,6 +48,10 @@ func (wid WidgetOption) apply(cfg interface{}) {
 	wid(cc)
 }
 
+func (wid *Widget) setKey(key string) {
+	wid.key = key
+}
+
 func (wid *Widget) setWidth(width float32) {
 	wid.width = unit.Dp(width)
 }
@@ -63,6 +68,22 @@ func (wid *Widget) setColor(c color.NRGBA) {
 	wid.fgColor = c
 }
 
+// KeyOption is the option type for widget keys.
+type KeyOption string
+
+// Key is an option parameter that gives the widget a name that is unique within its form.
+// When the form is rebuilt, the new widget with the same key takes over the state of the old one,
+// like the text and caret of an edit, the focus, the scroll position of a list or the splitter ratio.
+func Key(name string) KeyOption {
+	return KeyOption(name)
+}
+
+func (k KeyOption) apply(cfg interface{}) {
+	if w, ok := cfg.(interface{ setKey(string) }); ok {
+		w.setKey(string(k))
+	}
+}
+
 // Pad is used to set default widget paddings
 func (wid *Widget) Pad(t, r, b, l float32) {
 	wid.padding = layout.Inset{Top: unit.Dp(t), Bottom: unit.Dp(b), Left: unit.Dp(l), Right: unit.Dp(r)}